@@ -0,0 +1,125 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/usecase"
+)
+
+// Alias is an alternative name of method, for example a previous name of renamed method.
+type Alias struct {
+	// Name is a method name of alias.
+	Name string
+
+	// Params optionally converts params of alias call to params of method.
+	Params func(params json.RawMessage) (json.RawMessage, error)
+
+	// Result optionally converts method result to result of alias call.
+	Result func(result json.RawMessage) (json.RawMessage, error)
+
+	// Input is an optional sample of alias params for documentation, by default params of use case are
+	// documented if there is no Params adapter.
+	Input interface{}
+
+	// Output is an optional sample of alias result for documentation, by default result of use case is
+	// documented if there is no Result adapter.
+	Output interface{}
+}
+
+// AddAlias registers use case interactor with an alternative method name.
+//
+// Alias is documented as deprecated, use RequestMeta.Alias to find which alias was called.
+// Params and result of alias are documented with Input and Output, or with schemas of use case if there
+// are no adapters, adapted params or result without documentation type have no schema.
+// Params and result validation is applied to params and result of use case, after and before adapters.
+func (h *Handler) AddAlias(a Alias, u usecase.Interactor, mw ...usecase.Middleware) {
+	var withName usecase.HasName
+
+	if !usecase.As(u, &withName) {
+		panic("use case name is required")
+	}
+
+	m := h.newMethod(u, mw...)
+	m.target = withName.Name()
+	m.paramsAdapter = a.Params
+	m.resultAdapter = a.Result
+
+	if a.Params != nil || a.Result != nil || a.Input != nil || a.Output != nil {
+		m.docs = aliasDocs(a, m.useCase)
+	}
+
+	desc := fmt.Sprintf("Deprecated alias of %s.", m.target)
+
+	h.register(a.Name, m,
+		[]func(op *openapi3.Operation) error{
+			func(op *openapi3.Operation) error {
+				op.WithDeprecated(true)
+				op.WithDescription(aliasDescription(desc, op.Description))
+
+				return nil
+			},
+		},
+		[]func(rm *OpenRPCMethod) error{
+			func(rm *OpenRPCMethod) error {
+				rm.Deprecated = true
+
+				var d *string
+				if rm.Description != "" {
+					d = &rm.Description
+				}
+
+				rm.Description = aliasDescription(desc, d)
+
+				return nil
+			},
+		},
+	)
+}
+
+// aliasPorts overrides input and output of use case for documentation.
+type aliasPorts struct {
+	usecase.Interactor
+	input  interface{}
+	output interface{}
+}
+
+func (a aliasPorts) InputPort() interface{} {
+	return a.input
+}
+
+func (a aliasPorts) OutputPort() interface{} {
+	return a.output
+}
+
+// aliasDocs returns use case with documented input and output of alias.
+func aliasDocs(a Alias, u usecase.Interactor) usecase.Interactor {
+	var (
+		hasInput  usecase.HasInputPort
+		hasOutput usecase.HasOutputPort
+		ports     = aliasPorts{input: a.Input, output: a.Output}
+	)
+
+	if ports.input == nil && a.Params == nil && usecase.As(u, &hasInput) {
+		ports.input = hasInput.InputPort()
+	}
+
+	if ports.output == nil && a.Result == nil && usecase.As(u, &hasOutput) {
+		ports.output = hasOutput.OutputPort()
+	}
+
+	return usecase.Wrap(u, usecase.MiddlewareFunc(func(next usecase.Interactor) usecase.Interactor {
+		ports.Interactor = next
+
+		return ports
+	}))
+}
+
+func aliasDescription(desc string, orig *string) string {
+	if orig == nil || *orig == "" {
+		return desc
+	}
+
+	return desc + "\n\n" + *orig
+}
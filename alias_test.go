@@ -0,0 +1,149 @@
+package jsonrpc_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/jsonrpc"
+	"github.com/swaggest/usecase"
+)
+
+func TestHandler_AddAlias(t *testing.T) {
+	var metas []jsonrpc.RequestMeta
+
+	h := &jsonrpc.Handler{}
+	h.OpenAPI = &jsonrpc.OpenAPI{}
+	h.OpenRPC = &jsonrpc.OpenRPC{}
+	h.Validator = &jsonrpc.JSONSchemaValidator{}
+	h.Middlewares = append(h.Middlewares, usecase.MiddlewareFunc(func(next usecase.Interactor) usecase.Interactor {
+		return usecase.Interact(func(ctx context.Context, input, output interface{}) error {
+			m, _ := jsonrpc.MetaFromContext(ctx)
+			metas = append(metas, m)
+
+			return next.Interact(ctx, input, output)
+		})
+	}))
+
+	h.Add(echoUseCase())
+
+	// Previous version of method had "text" param and returned only text.
+	h.AddAlias(jsonrpc.Alias{
+		Name: "echoV0",
+		Params: func(params json.RawMessage) (json.RawMessage, error) {
+			var p struct {
+				Text string `json:"text"`
+			}
+
+			if err := json.Unmarshal(params, &p); err != nil {
+				return nil, err
+			}
+
+			return json.Marshal(map[string]interface{}{"a": p.Text, "b": 1})
+		},
+		Result: func(result json.RawMessage) (json.RawMessage, error) {
+			var r struct {
+				A string `json:"a"`
+			}
+
+			if err := json.Unmarshal(result, &r); err != nil {
+				return nil, err
+			}
+
+			return json.Marshal(r.A)
+		},
+	}, echoUseCase())
+
+	h.AddAlias(jsonrpc.Alias{Name: "repeat"}, echoUseCase())
+
+	type textParams struct {
+		Text string `json:"text"`
+	}
+
+	// Alias with documented params and adapted result without documentation.
+	h.AddAlias(jsonrpc.Alias{
+		Name:  "echoText",
+		Input: new(textParams),
+		Params: func(params json.RawMessage) (json.RawMessage, error) {
+			var p textParams
+
+			if err := json.Unmarshal(params, &p); err != nil {
+				return nil, err
+			}
+
+			return json.Marshal(map[string]interface{}{"a": p.Text, "b": 1})
+		},
+		Result: func(result json.RawMessage) (json.RawMessage, error) {
+			return result, nil
+		},
+	}, echoUseCase())
+
+	call := func(body string) string {
+		req, err := http.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(body)))
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		return w.Body.String()
+	}
+
+	assert.Equal(t, `{"jsonrpc":"2.0","result":"abc","id":1}`,
+		call(`{"jsonrpc":"2.0","method":"echoV0","params":{"text":"abc"},"id":1}`))
+	assert.Equal(t, `{"jsonrpc":"2.0","result":{"b":2,"a":"abc"},"id":1}`,
+		call(`{"jsonrpc":"2.0","method":"repeat","params":{"a":"abc","b":2},"id":1}`))
+	assert.Equal(t, `{"jsonrpc":"2.0","result":{"b":2,"a":"abc"},"id":1}`,
+		call(`{"jsonrpc":"2.0","method":"echo","params":{"a":"abc","b":2},"id":1}`))
+
+	// Adapted params are validated.
+	assert.Contains(t, call(`{"jsonrpc":"2.0","method":"echoV0","params":{"text":"a"},"id":1}`),
+		`"message":"invalid parameters"`)
+
+	require.Len(t, metas, 4)
+	assert.Equal(t, "echo", metas[0].Method)
+	assert.Equal(t, "echoV0", metas[0].Alias)
+	assert.Equal(t, "echo", metas[1].Method)
+	assert.Equal(t, "repeat", metas[1].Alias)
+	assert.Equal(t, "echo", metas[2].Method)
+	assert.Empty(t, metas[2].Alias)
+
+	op := h.OpenAPI.Reflector().SpecEns().Paths.MapOfPathItemValues["repeat"].MapOfOperationValues["post"]
+	require.NotNil(t, op.ID)
+	assert.Equal(t, "repeat", *op.ID)
+	assert.True(t, *op.Deprecated)
+	assert.Equal(t, "Deprecated alias of echo.", *op.Description)
+
+	methods := map[string]jsonrpc.OpenRPCMethod{}
+
+	for _, m := range h.OpenRPC.SpecEns().Methods {
+		assert.Equal(t, m.Name != "echo", m.Deprecated, m.Name)
+
+		methods[m.Name] = m
+	}
+
+	// Aliases without adapters are documented with schemas of use case.
+	assert.Equal(t, methods["echo"].Params, methods["repeat"].Params)
+	assert.Equal(t, methods["echo"].Result, methods["repeat"].Result)
+
+	// Adapted params and result are not documented with schemas of use case.
+	assert.Empty(t, methods["echoV0"].Params)
+	assert.Nil(t, methods["echoV0"].Result)
+
+	require.Len(t, methods["echoText"].Params, 1)
+	assert.Equal(t, "text", methods["echoText"].Params[0].Name)
+	assert.Nil(t, methods["echoText"].Result)
+
+	op = h.OpenAPI.Reflector().SpecEns().Paths.MapOfPathItemValues["echoV0"].MapOfOperationValues["post"]
+	assert.Nil(t, op.RequestBody)
+
+	// Validation schemas of use case are applied to adapted params.
+	assert.Equal(t, `{"jsonrpc":"2.0","result":{"b":1,"a":"abc"},"id":1}`,
+		call(`{"jsonrpc":"2.0","method":"echoText","params":{"text":"abc"},"id":1}`))
+	assert.Contains(t, call(`{"jsonrpc":"2.0","method":"echoText","params":{"text":"a"},"id":1}`),
+		`"message":"invalid parameters"`)
+}
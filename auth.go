@@ -0,0 +1,330 @@
+package jsonrpc
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+)
+
+// Principal is an authenticated subject of request.
+type Principal struct {
+	// Name identifies subject, for example user name or certificate common name.
+	Name string
+
+	// Permissions are granted to subject.
+	Permissions []string
+
+	// Data holds application-specific details.
+	Data interface{}
+}
+
+// HasPermission checks if permission is granted to principal.
+func (p *Principal) HasPermission(permission string) bool {
+	for _, perm := range p.Permissions {
+		if perm == permission {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Authenticator authenticates HTTP request.
+//
+// Authenticate returns nil principal and nil error if request has no credentials.
+type Authenticator interface {
+	Authenticate(r *http.Request) (*Principal, error)
+}
+
+// AuthenticatorFunc implements Authenticator with a function.
+type AuthenticatorFunc func(r *http.Request) (*Principal, error)
+
+// Authenticate authenticates HTTP request.
+func (f AuthenticatorFunc) Authenticate(r *http.Request) (*Principal, error) {
+	return f(r)
+}
+
+// HasSecuritySchemes documents security schemes of Authenticator in OpenAPI.
+type HasSecuritySchemes interface {
+	SecuritySchemes() map[string]openapi3.SecurityScheme
+}
+
+// HasRequiredPermissions declares that method requires authenticated principal with permissions.
+type HasRequiredPermissions interface {
+	RequiredPermissions() []string
+}
+
+// PrincipalFromContext returns authenticated principal of request.
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	ar, _ := ctx.Value(authCtxKey{}).(authResult)
+
+	return ar.principal, ar.principal != nil
+}
+
+type authCtxKey struct{}
+
+type authResult struct {
+	principal *Principal
+	err       error
+}
+
+var errMissingCredentials = errors.New("missing credentials")
+
+// withHTTPRequest adds originating HTTP request and authentication result to context.
+func (h *Handler) withHTTPRequest(ctx context.Context, r *http.Request) context.Context {
+	ctx = context.WithValue(ctx, httpRequestCtxKey{}, r)
+
+	if h.Authenticator == nil {
+		return ctx
+	}
+
+	p, err := h.Authenticator.Authenticate(r)
+
+	return context.WithValue(ctx, authCtxKey{}, authResult{principal: p, err: err})
+}
+
+// authorize checks that request principal has permissions.
+func authorize(ctx context.Context, permissions []string) error {
+	ar, _ := ctx.Value(authCtxKey{}).(authResult)
+
+	if ar.err != nil {
+		return status.Wrap(ar.err, status.Unauthenticated)
+	}
+
+	if ar.principal == nil {
+		return status.Wrap(errMissingCredentials, status.Unauthenticated)
+	}
+
+	for _, perm := range permissions {
+		if !ar.principal.HasPermission(perm) {
+			return status.Wrap(fmt.Errorf("missing permission: %s", perm), status.PermissionDenied)
+		}
+	}
+
+	return nil
+}
+
+// RequirePermissions is a method middleware that requires authenticated principal with all of permissions.
+//
+// Permissions are checked by Handler before params decoding, requirements of nested
+// middlewares (for example of group and method) are combined.
+func RequirePermissions(permissions ...string) usecase.Middleware {
+	return usecase.MiddlewareFunc(func(next usecase.Interactor) usecase.Interactor {
+		pi := &permissionsInteractor{Interactor: next}
+		pi.permissions = append(pi.permissions, permissions...)
+
+		var inner HasRequiredPermissions
+		if usecase.As(next, &inner) {
+			pi.permissions = append(pi.permissions, inner.RequiredPermissions()...)
+		}
+
+		return pi
+	})
+}
+
+type permissionsInteractor struct {
+	usecase.Interactor
+	permissions []string
+}
+
+func (p *permissionsInteractor) RequiredPermissions() []string {
+	return p.permissions
+}
+
+// Authenticators tries authenticators in order and returns first authenticated principal.
+type Authenticators []Authenticator
+
+// Authenticate authenticates HTTP request.
+func (as Authenticators) Authenticate(r *http.Request) (*Principal, error) {
+	var firstErr error
+
+	for _, a := range as {
+		p, err := a.Authenticate(r)
+		if p != nil {
+			return p, nil
+		}
+
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return nil, firstErr
+}
+
+// SecuritySchemes returns security schemes of authenticators.
+func (as Authenticators) SecuritySchemes() map[string]openapi3.SecurityScheme {
+	res := make(map[string]openapi3.SecurityScheme)
+
+	for _, a := range as {
+		if s, ok := a.(HasSecuritySchemes); ok {
+			for name, scheme := range s.SecuritySchemes() {
+				res[name] = scheme
+			}
+		}
+	}
+
+	return res
+}
+
+// BearerAuth authenticates requests with "Authorization: Bearer <token>" header.
+type BearerAuth struct {
+	// SchemeName is a name of OpenAPI security scheme, default "bearerAuth".
+	SchemeName string
+
+	Validate func(ctx context.Context, token string) (*Principal, error)
+}
+
+// Authenticate authenticates HTTP request.
+func (a BearerAuth) Authenticate(r *http.Request) (*Principal, error) {
+	auth := r.Header.Get("Authorization")
+
+	if len(auth) < 7 || !strings.EqualFold(auth[:7], "bearer ") {
+		return nil, nil
+	}
+
+	return a.Validate(r.Context(), auth[7:])
+}
+
+// SecuritySchemes documents security scheme.
+func (a BearerAuth) SecuritySchemes() map[string]openapi3.SecurityScheme {
+	return map[string]openapi3.SecurityScheme{
+		schemeName(a.SchemeName, "bearerAuth"): {
+			HTTPSecurityScheme: (&openapi3.HTTPSecurityScheme{}).WithScheme("bearer"),
+		},
+	}
+}
+
+// BasicAuth authenticates requests with HTTP basic authentication.
+type BasicAuth struct {
+	// SchemeName is a name of OpenAPI security scheme, default "basicAuth".
+	SchemeName string
+
+	Validate func(ctx context.Context, user, password string) (*Principal, error)
+}
+
+// Authenticate authenticates HTTP request.
+func (a BasicAuth) Authenticate(r *http.Request) (*Principal, error) {
+	user, password, ok := r.BasicAuth()
+	if !ok {
+		return nil, nil
+	}
+
+	return a.Validate(r.Context(), user, password)
+}
+
+// SecuritySchemes documents security scheme.
+func (a BasicAuth) SecuritySchemes() map[string]openapi3.SecurityScheme {
+	return map[string]openapi3.SecurityScheme{
+		schemeName(a.SchemeName, "basicAuth"): {
+			HTTPSecurityScheme: (&openapi3.HTTPSecurityScheme{}).WithScheme("basic"),
+		},
+	}
+}
+
+// APIKeyAuth authenticates requests with API key in header.
+type APIKeyAuth struct {
+	// SchemeName is a name of OpenAPI security scheme, default "apiKeyAuth".
+	SchemeName string
+
+	// Header is a name of header with API key, default "X-API-Key".
+	Header string
+
+	Validate func(ctx context.Context, key string) (*Principal, error)
+}
+
+func (a APIKeyAuth) header() string {
+	if a.Header == "" {
+		return "X-API-Key"
+	}
+
+	return a.Header
+}
+
+// Authenticate authenticates HTTP request.
+func (a APIKeyAuth) Authenticate(r *http.Request) (*Principal, error) {
+	key := r.Header.Get(a.header())
+	if key == "" {
+		return nil, nil
+	}
+
+	return a.Validate(r.Context(), key)
+}
+
+// SecuritySchemes documents security scheme.
+func (a APIKeyAuth) SecuritySchemes() map[string]openapi3.SecurityScheme {
+	return map[string]openapi3.SecurityScheme{
+		schemeName(a.SchemeName, "apiKeyAuth"): {
+			APIKeySecurityScheme: &openapi3.APIKeySecurityScheme{
+				Name: a.header(),
+				In:   openapi3.APIKeySecuritySchemeInHeader,
+			},
+		},
+	}
+}
+
+// CertificateAuth authenticates requests with verified TLS client certificate.
+type CertificateAuth struct {
+	Validate func(ctx context.Context, cert *x509.Certificate) (*Principal, error)
+}
+
+// Authenticate authenticates HTTP request.
+func (a CertificateAuth) Authenticate(r *http.Request) (*Principal, error) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil, nil
+	}
+
+	return a.Validate(r.Context(), r.TLS.VerifiedChains[0][0])
+}
+
+func schemeName(name, def string) string {
+	if name == "" {
+		return def
+	}
+
+	return name
+}
+
+// securityAnnotations documents security requirements of method in OpenAPI.
+func (h *Handler) securityAnnotations(m method) []func(op *openapi3.Operation) error {
+	if !m.requiresAuth {
+		return nil
+	}
+
+	var schemes map[string]openapi3.SecurityScheme
+
+	if s, ok := h.Authenticator.(HasSecuritySchemes); ok {
+		schemes = s.SecuritySchemes()
+	}
+
+	h.OpenAPI.addSecuritySchemes(schemes)
+
+	names := make([]string, 0, len(schemes))
+	for name := range schemes {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return []func(op *openapi3.Operation) error{
+		func(op *openapi3.Operation) error {
+			for _, name := range names {
+				op.Security = append(op.Security, map[string][]string{name: {}})
+			}
+
+			if len(m.permissions) > 0 {
+				op.WithMapOfAnythingItem(xPermissions, m.permissions)
+			}
+
+			return nil
+		},
+	}
+}
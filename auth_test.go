@@ -0,0 +1,106 @@
+package jsonrpc_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/jsonrpc"
+	"github.com/swaggest/usecase"
+)
+
+func TestHandler_Authenticator(t *testing.T) {
+	h := jsonrpc.Handler{}
+	h.OpenAPI = &jsonrpc.OpenAPI{}
+	h.Authenticator = jsonrpc.Authenticators{
+		jsonrpc.BearerAuth{Validate: func(ctx context.Context, token string) (*jsonrpc.Principal, error) {
+			switch token {
+			case "admin":
+				return &jsonrpc.Principal{Name: "root", Permissions: []string{"users.read", "users.write"}}, nil
+			case "reader":
+				return &jsonrpc.Principal{Name: "bob", Permissions: []string{"users.read"}}, nil
+			}
+
+			return nil, errors.New("invalid token")
+		}},
+		jsonrpc.APIKeyAuth{Validate: func(ctx context.Context, key string) (*jsonrpc.Principal, error) {
+			return &jsonrpc.Principal{Name: "service"}, nil
+		}},
+	}
+
+	whoami := usecase.NewIOI(nil, new(string), func(ctx context.Context, input, output interface{}) error {
+		if p, ok := jsonrpc.PrincipalFromContext(ctx); ok {
+			*output.(*string) = p.Name
+		}
+
+		return nil
+	})
+	whoami.SetName("whoami")
+
+	h.Add(whoami)
+	h.Add(echoUseCase(), jsonrpc.RequirePermissions("users.read"))
+	h.Group("admin.", jsonrpc.RequirePermissions("users.read")).Add(echoUseCase(), jsonrpc.RequirePermissions("users.write"))
+
+	call := func(method string, header http.Header) string {
+		req, err := http.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(
+			`{"jsonrpc":"2.0","method":"`+method+`","params":{"a":"abc","b":1},"id":1}`)))
+		require.NoError(t, err)
+
+		for k, v := range header {
+			req.Header[k] = v
+		}
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		return w.Body.String()
+	}
+
+	bearer := func(token string) http.Header {
+		return http.Header{"Authorization": {"Bearer " + token}}
+	}
+
+	assert.Equal(t, `{"jsonrpc":"2.0","result":"","id":1}`, call("whoami", nil))
+	assert.Equal(t, `{"jsonrpc":"2.0","result":"bob","id":1}`, call("whoami", bearer("reader")))
+	assert.Equal(t, `{"jsonrpc":"2.0","result":"service","id":1}`, call("whoami", http.Header{"X-Api-Key": {"abc"}}))
+
+	assert.Equal(t, `{"jsonrpc":"2.0","error":{"code":-32016,"message":"unauthenticated","data":"unauthenticated: missing credentials"},"id":1}`,
+		call("echo", nil))
+	assert.Equal(t, `{"jsonrpc":"2.0","error":{"code":-32016,"message":"unauthenticated","data":"unauthenticated: invalid token"},"id":1}`,
+		call("echo", bearer("foo")))
+	assert.Equal(t, `{"jsonrpc":"2.0","result":{"b":1,"a":"abc"},"id":1}`, call("echo", bearer("reader")))
+
+	assert.Equal(t, `{"jsonrpc":"2.0","error":{"code":-32007,"message":"permission denied","data":"permission denied: missing permission: users.write"},"id":1}`,
+		call("admin.echo", bearer("reader")))
+	assert.Equal(t, `{"jsonrpc":"2.0","result":{"b":1,"a":"abc"},"id":1}`, call("admin.echo", bearer("admin")))
+
+	schema, err := json.Marshal(h.OpenAPI.Reflector().Spec)
+	require.NoError(t, err)
+
+	var doc struct {
+		Components struct {
+			SecuritySchemes map[string]interface{} `json:"securitySchemes"`
+		} `json:"components"`
+		Paths map[string]map[string]struct {
+			Security    []map[string][]string `json:"security"`
+			Permissions []string              `json:"x-required-permissions"`
+		} `json:"paths"`
+	}
+
+	require.NoError(t, json.Unmarshal(schema, &doc))
+
+	assert.Equal(t, map[string]interface{}{
+		"bearerAuth": map[string]interface{}{"type": "http", "scheme": "bearer"},
+		"apiKeyAuth": map[string]interface{}{"type": "apiKey", "name": "X-API-Key", "in": "header"},
+	}, doc.Components.SecuritySchemes)
+
+	assert.Empty(t, doc.Paths["whoami"]["post"].Security)
+	assert.Equal(t, []map[string][]string{{"apiKeyAuth": {}}, {"bearerAuth": {}}}, doc.Paths["admin.echo"]["post"].Security)
+	assert.Equal(t, []string{"users.read", "users.write"}, doc.Paths["admin.echo"]["post"].Permissions)
+}
@@ -0,0 +1,232 @@
+package jsonrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// serveBatch decodes batch requests one by one and starts execution of every request as soon as it is read.
+//
+// If batch decoding fails after some requests were started, responses of started requests are returned
+// together with an error response of null id. With Handler.MaxBatchSize requests are started only after
+// the whole batch is read, so that too large batch is rejected entirely.
+func (h *Handler) serveBatch(ctx context.Context, r io.Reader) []byte {
+	dec := h.newDecoder(r)
+
+	// Opening bracket is already checked by the caller.
+	if _, err := dec.Token(); err != nil {
+		return h.readFailure(err, CodeInvalidRequest)
+	}
+
+	ctx = context.WithValue(ctx, batchCtxKey{}, true)
+
+	var (
+		b     = h.newBatch(ctx)
+		resps []*Response
+		cnt   int
+		fail  *Error
+
+		// pending holds requests until batch size is checked.
+		pending []batchItem
+	)
+
+	for dec.More() {
+		if h.MaxBatchSize > 0 && cnt >= h.MaxBatchSize {
+			fail = &Error{
+				Code:    CodeInvalidRequest,
+				Message: fmt.Sprintf("batch is too large, max %d", h.MaxBatchSize),
+			}
+
+			break
+		}
+
+		cnt++
+
+		var req Request
+
+		resp := &Response{
+			JSONRPC: ver,
+		}
+
+		if err := dec.Decode(&req); err != nil {
+			// Decoder can continue after a request of invalid type.
+			if isValueError(err) {
+				resp.Error = &Error{
+					Code:    CodeInvalidRequest,
+					Message: fmt.Sprintf("failed to unmarshal request: %s", err.Error()),
+				}
+				resps = append(resps, resp)
+
+				continue
+			}
+
+			fail = h.readError(err, CodeInvalidRequest)
+
+			break
+		}
+
+		if req.ID != nil {
+			resp.ID = req.ID
+			resps = append(resps, resp)
+		}
+
+		if req.JSONRPC != ver {
+			resp.Error = &Error{
+				Code:    CodeInvalidRequest,
+				Message: fmt.Sprintf("invalid jsonrpc value: %q", req.JSONRPC),
+			}
+
+			continue
+		}
+
+		if h.MaxBatchSize > 0 {
+			pending = append(pending, batchItem{req: req, resp: resp})
+
+			continue
+		}
+
+		b.run(req, resp)
+	}
+
+	if fail == nil {
+		if _, err := dec.Token(); err != nil {
+			fail = h.readError(err, CodeInvalidRequest)
+		}
+	}
+
+	if fail == nil {
+		for _, it := range pending {
+			b.run(it.req, it.resp)
+		}
+	}
+
+	b.wait()
+
+	if fail != nil {
+		if !b.started {
+			return h.failure(errors.New(fail.Message), fail.Code)
+		}
+
+		resps = append(resps, &Response{JSONRPC: ver, Error: fail})
+	}
+
+	if resps == nil {
+		resps = []*Response{}
+	}
+
+	data, err := h.codec().Marshal(resps)
+	if err != nil {
+		return h.failure(err, CodeInternalError)
+	}
+
+	return data
+}
+
+type batchItem struct {
+	req  Request
+	resp *Response
+}
+
+// batch executes batch requests according to concurrency options of Handler.
+type batch struct {
+	h       *Handler
+	ctx     context.Context
+	wg      sync.WaitGroup
+	started bool
+
+	// sem limits concurrency of batch, can be nil.
+	sem chan struct{}
+}
+
+func (h *Handler) newBatch(ctx context.Context) *batch {
+	b := &batch{
+		h:   h,
+		ctx: ctx,
+	}
+
+	if h.BatchConcurrency > 0 {
+		b.sem = make(chan struct{}, h.BatchConcurrency)
+	}
+
+	h.inFlightOnce.Do(func() {
+		if h.MaxInFlight > 0 {
+			h.inFlight = make(chan struct{}, h.MaxInFlight)
+		}
+	})
+
+	return b
+}
+
+// run invokes request, it blocks while concurrency limits are reached.
+func (b *batch) run(req Request, resp *Response) {
+	b.started = true
+
+	if !b.acquire(resp) {
+		return
+	}
+
+	if b.h.SequentialBatch {
+		defer b.release()
+
+		b.h.invoke(b.ctx, req, resp)
+
+		return
+	}
+
+	b.wg.Add(1)
+
+	go func() {
+		defer func() {
+			b.release()
+			b.wg.Done()
+		}()
+
+		b.h.invoke(b.ctx, req, resp)
+	}()
+}
+
+// wait blocks until all requests are done.
+func (b *batch) wait() {
+	b.wg.Wait()
+}
+
+func (b *batch) acquire(resp *Response) bool {
+	if b.sem != nil {
+		select {
+		case b.sem <- struct{}{}:
+		case <-b.ctx.Done():
+			b.h.interactErr(resp, b.ctx.Err())
+
+			return false
+		}
+	}
+
+	if b.h.inFlight != nil {
+		select {
+		case b.h.inFlight <- struct{}{}:
+		case <-b.ctx.Done():
+			if b.sem != nil {
+				<-b.sem
+			}
+
+			b.h.interactErr(resp, b.ctx.Err())
+
+			return false
+		}
+	}
+
+	return true
+}
+
+func (b *batch) release() {
+	if b.h.inFlight != nil {
+		<-b.h.inFlight
+	}
+
+	if b.sem != nil {
+		<-b.sem
+	}
+}
@@ -0,0 +1,107 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+)
+
+// MethodCancelRequest is a notification method of persistent connection that cancels in-flight call by id.
+//
+// Params of notification are {"id": <id of call>}, as in Language Server Protocol.
+const MethodCancelRequest = "$/cancelRequest"
+
+// CodeRequestCancelled is an error code of call that was cancelled with MethodCancelRequest.
+const CodeRequestCancelled = ErrorCode(-32800)
+
+var errMissingID = errors.New("missing id")
+
+type cancelParams struct {
+	ID *interface{} `json:"id"`
+}
+
+// inFlightCall is a call of persistent connection that can be cancelled.
+type inFlightCall struct {
+	cancel    context.CancelFunc
+	cancelled bool
+}
+
+// track registers call of persistent connection for cancellation.
+//
+// Returned release function must be called when call is done, it reports if call was cancelled by peer.
+func (p *Peer) track(ctx context.Context, id *interface{}) (context.Context, func() bool) {
+	ctx, cancel := context.WithCancel(ctx)
+	c := &inFlightCall{cancel: cancel}
+	key := idKey(id)
+
+	p.mu.Lock()
+	if p.calls == nil {
+		p.calls = make(map[string]*inFlightCall)
+	}
+
+	p.calls[key] = c
+	p.mu.Unlock()
+
+	return ctx, func() bool {
+		cancel()
+
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		if p.calls[key] == c {
+			delete(p.calls, key)
+		}
+
+		return c.cancelled
+	}
+}
+
+// cancel cancels in-flight call by id.
+func (p *Peer) cancel(id *interface{}) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	c, ok := p.calls[idKey(id)]
+	if !ok {
+		return false
+	}
+
+	c.cancelled = true
+	c.cancel()
+
+	return true
+}
+
+// cancelRequest handles MethodCancelRequest.
+func (h *Handler) cancelRequest(p *Peer, req Request, resp *Response) {
+	var params cancelParams
+
+	if err := h.codec().Unmarshal(req.Params, &params); err != nil || params.ID == nil {
+		if err == nil {
+			err = errMissingID
+		}
+
+		h.errResp(resp, "failed to unmarshal parameters", CodeInvalidParams, err)
+
+		return
+	}
+
+	// Unknown or finished calls are ignored.
+	p.cancel(params.ID)
+
+	resp.Result = json.RawMessage("null")
+}
+
+// trackCall makes call of persistent connection cancellable with MethodCancelRequest.
+func trackCall(ctx context.Context, req Request) (context.Context, func() bool) {
+	if req.ID == nil {
+		return ctx, func() bool { return false }
+	}
+
+	p, ok := PeerFromContext(ctx)
+	if !ok {
+		return ctx, func() bool { return false }
+	}
+
+	return p.track(ctx, req.ID)
+}
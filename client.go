@@ -0,0 +1,190 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// Client calls JSON-RPC 2.0 methods over HTTP.
+type Client struct {
+	// URL is an address of JSON-RPC endpoint.
+	URL string
+
+	// Transport sends HTTP requests, http.DefaultTransport is used if nil.
+	Transport http.RoundTripper
+
+	// Header is added to every HTTP request.
+	Header http.Header
+
+	seq int64
+}
+
+// Call invokes JSON-RPC method with typed params and result.
+func Call[In, Out any](ctx context.Context, c *Client, method string, in In) (Out, error) {
+	var out Out
+
+	err := c.Invoke(ctx, method, in, &out)
+
+	return out, err
+}
+
+// Invoke calls JSON-RPC method and decodes result into a value pointed by result.
+//
+// Error response is returned as *Error.
+func (c *Client) Invoke(ctx context.Context, method string, params, result interface{}) error {
+	req, err := c.request(method, params, c.nextID())
+	if err != nil {
+		return err
+	}
+
+	reqBody, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	respBody, err := c.do(ctx, reqBody)
+	if err != nil {
+		return err
+	}
+
+	var resp Response
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return resp.decodeResult(result)
+}
+
+// Notify sends JSON-RPC notification, server does not reply to notifications.
+func (c *Client) Notify(ctx context.Context, method string, params interface{}) error {
+	req, err := c.request(method, params, nil)
+	if err != nil {
+		return err
+	}
+
+	reqBody, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	_, err = c.do(ctx, reqBody)
+
+	return err
+}
+
+func (c *Client) nextID() *interface{} {
+	var id interface{} = atomic.AddInt64(&c.seq, 1)
+
+	return &id
+}
+
+func (c *Client) request(method string, params interface{}, id *interface{}) (Request, error) {
+	req := Request{
+		JSONRPC: ver,
+		Method:  method,
+		ID:      id,
+	}
+
+	if params != nil {
+		p, err := json.Marshal(params)
+		if err != nil {
+			return req, fmt.Errorf("failed to marshal parameters: %w", err)
+		}
+
+		req.Params = p
+	}
+
+	return req, nil
+}
+
+func (c *Client) do(ctx context.Context, reqBody []byte) (respBody []byte, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL, bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range c.Header {
+		req.Header[k] = v
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	if deadline, ok := ctx.Deadline(); ok && req.Header.Get(TimeoutHeader) == "" {
+		req.Header.Set(TimeoutHeader, time.Until(deadline).String())
+	}
+
+	transport := c.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if clErr := resp.Body.Close(); clErr != nil && err == nil {
+			err = clErr
+		}
+	}()
+
+	respBody, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return nil, fmt.Errorf("%w: %s", errUnexpectedStatus, resp.Status)
+	}
+
+	return respBody, nil
+}
+
+var errUnexpectedStatus = errors.New("unexpected response status")
+
+func (resp *Response) decodeResult(result interface{}) error {
+	if resp.Error != nil {
+		resp.Error.restoreData()
+
+		return resp.Error
+	}
+
+	if result == nil || len(resp.Result) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(resp.Result, result); err != nil {
+		return fmt.Errorf("failed to unmarshal result: %w", err)
+	}
+
+	return nil
+}
+
+// restoreData converts decoded structured error data back to structuredErrorData.
+func (e *Error) restoreData() {
+	m, ok := e.Data.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	msg, ok := m["error"].(string)
+	if !ok {
+		return
+	}
+
+	d := structuredErrorData{Message: msg}
+
+	if ctx, ok := m["context"].(map[string]interface{}); ok {
+		d.Context = ctx
+	}
+
+	e.Data = d
+}
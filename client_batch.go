@@ -0,0 +1,171 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var (
+	errBatchNotSent    = errors.New("batch is not sent")
+	errMissingResponse = errors.New("missing response")
+)
+
+// Batch accumulates calls and notifications to send them in a single request.
+//
+// Batch is not safe for concurrent use.
+type Batch struct {
+	c    *Client
+	reqs []Request
+
+	// pending maps encoded request id to call result.
+	pending map[string]*batchResult
+
+	err error
+}
+
+type batchResult struct {
+	sent bool
+	resp *Response
+	err  error
+}
+
+// Pending is a typed result of a batched call, it is available after Batch.Send.
+type Pending[Out any] struct {
+	res *batchResult
+}
+
+// Result returns decoded result or error of the call.
+func (p *Pending[Out]) Result() (Out, error) {
+	var out Out
+
+	switch {
+	case p.res.err != nil:
+		return out, p.res.err
+	case !p.res.sent:
+		return out, errBatchNotSent
+	case p.res.resp == nil:
+		return out, errMissingResponse
+	}
+
+	err := p.res.resp.decodeResult(&out)
+
+	return out, err
+}
+
+// Batch creates an empty batch of calls.
+func (c *Client) Batch() *Batch {
+	return &Batch{
+		c:       c,
+		pending: make(map[string]*batchResult),
+	}
+}
+
+// AddCall adds typed method call to the batch.
+func AddCall[In, Out any](b *Batch, method string, in In) *Pending[Out] {
+	p := &Pending[Out]{res: &batchResult{}}
+
+	id := b.c.nextID()
+
+	req, err := b.c.request(method, in, id)
+	if err != nil {
+		p.res.err = err
+
+		return p
+	}
+
+	b.reqs = append(b.reqs, req)
+	b.pending[idKey(id)] = p.res
+
+	return p
+}
+
+// Notify adds notification to the batch.
+func (b *Batch) Notify(method string, params interface{}) {
+	req, err := b.c.request(method, params, nil)
+	if err != nil {
+		if b.err == nil {
+			b.err = err
+		}
+
+		return
+	}
+
+	b.reqs = append(b.reqs, req)
+}
+
+// Send sends accumulated requests and distributes responses to pending calls by id.
+//
+// Error is returned if batch could not be sent or response could not be decoded,
+// errors of individual calls are available with Pending.Result.
+func (b *Batch) Send(ctx context.Context) error {
+	if b.err != nil {
+		return b.err
+	}
+
+	if len(b.reqs) == 0 {
+		return nil
+	}
+
+	err := b.send(ctx)
+
+	for _, res := range b.pending {
+		res.sent = true
+
+		if err != nil && res.err == nil {
+			res.err = err
+		}
+	}
+
+	return err
+}
+
+func (b *Batch) send(ctx context.Context) error {
+	reqBody, err := json.Marshal(b.reqs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	respBody, err := b.c.do(ctx, reqBody)
+	if err != nil {
+		return err
+	}
+
+	if len(respBody) == 0 {
+		return nil
+	}
+
+	var resps []Response
+	if err := json.Unmarshal(respBody, &resps); err != nil {
+		// Server may respond with a single error for malformed batch.
+		var resp Response
+		if json.Unmarshal(respBody, &resp) == nil && resp.Error != nil {
+			return resp.decodeResult(nil)
+		}
+
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	for i := range resps {
+		resp := &resps[i]
+		if resp.ID == nil {
+			continue
+		}
+
+		if res, ok := b.pending[idKey(resp.ID)]; ok {
+			res.resp = resp
+		}
+	}
+
+	return nil
+}
+
+func idKey(id *interface{}) string {
+	k, err := json.Marshal(id)
+	if err != nil {
+		return fmt.Sprintf("%v", *id)
+	}
+
+	return string(k)
+}
@@ -0,0 +1,114 @@
+package jsonrpc_test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/jsonrpc"
+	"github.com/swaggest/usecase"
+)
+
+type echoInput struct {
+	A string `json:"a" minLength:"3"`
+	B int    `json:"b" maximum:"8"`
+}
+
+type echoOutput struct {
+	B int    `json:"b"`
+	A string `json:"a"`
+}
+
+func echoUseCase() usecase.IOInteractorOf[echoInput, echoOutput] {
+	u := usecase.NewInteractor(func(ctx context.Context, input echoInput, output *echoOutput) error {
+		if input.A == "fail" {
+			return errors.New("failed")
+		}
+
+		output.A = input.A
+		output.B = input.B
+
+		return nil
+	})
+	u.SetName("echo")
+
+	return u
+}
+
+func TestCall(t *testing.T) {
+	h := &jsonrpc.Handler{}
+	h.OpenAPI = &jsonrpc.OpenAPI{}
+	h.Validator = &jsonrpc.JSONSchemaValidator{}
+	h.Add(echoUseCase())
+
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	c := jsonrpc.Client{URL: srv.URL}
+	ctx := context.Background()
+
+	out, err := jsonrpc.Call[echoInput, echoOutput](ctx, &c, "echo", echoInput{A: "abc", B: 5})
+	require.NoError(t, err)
+	assert.Equal(t, echoOutput{A: "abc", B: 5}, out)
+
+	_, err = jsonrpc.Call[echoInput, echoOutput](ctx, &c, "echo", echoInput{A: "fail"})
+	require.Error(t, err)
+	assert.Equal(t, "operation failed: failed", err.Error())
+
+	_, err = jsonrpc.Call[echoInput, echoOutput](ctx, &c, "echo", echoInput{A: "a", B: 9})
+	require.Error(t, err)
+
+	var rpcErr *jsonrpc.Error
+	require.True(t, errors.As(err, &rpcErr))
+	assert.Equal(t, jsonrpc.CodeInvalidParams, rpcErr.Code)
+
+	var fieldsErr jsonrpc.ErrWithFields
+	require.True(t, errors.As(err, &fieldsErr))
+	assert.Equal(t, "validation failed", fieldsErr.Error())
+	assert.Equal(t, map[string]interface{}{"params": []interface{}{
+		"#/a: length must be >= 3, but got 1", "#/b: must be <= 8 but found 9", "#: validation failed",
+	}}, fieldsErr.Fields())
+
+	_, err = jsonrpc.Call[echoInput, echoOutput](ctx, &c, "unknown", echoInput{})
+	require.True(t, errors.As(err, &rpcErr))
+	assert.Equal(t, jsonrpc.CodeMethodNotFound, rpcErr.Code)
+
+	assert.NoError(t, c.Notify(ctx, "echo", echoInput{A: "abc"}))
+}
+
+func TestBatch_Send(t *testing.T) {
+	h := &jsonrpc.Handler{}
+	h.OpenAPI = &jsonrpc.OpenAPI{}
+	h.Validator = &jsonrpc.JSONSchemaValidator{}
+	h.Add(echoUseCase())
+
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	c := jsonrpc.Client{URL: srv.URL}
+	b := c.Batch()
+
+	p1 := jsonrpc.AddCall[echoInput, echoOutput](b, "echo", echoInput{A: "abc", B: 1})
+	p2 := jsonrpc.AddCall[echoInput, echoOutput](b, "echo", echoInput{A: "fail"})
+	b.Notify("echo", echoInput{A: "def"})
+	p3 := jsonrpc.AddCall[echoInput, echoOutput](b, "echo", echoInput{A: "ghi", B: 3})
+
+	_, err := p1.Result()
+	assert.EqualError(t, err, "batch is not sent")
+
+	require.NoError(t, b.Send(context.Background()))
+
+	out, err := p1.Result()
+	require.NoError(t, err)
+	assert.Equal(t, echoOutput{A: "abc", B: 1}, out)
+
+	_, err = p2.Result()
+	assert.EqualError(t, err, "operation failed: failed")
+
+	out, err = p3.Result()
+	require.NoError(t, err)
+	assert.Equal(t, echoOutput{A: "ghi", B: 3}, out)
+}
@@ -0,0 +1,93 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// Codec encodes and decodes JSON-RPC messages, params and results.
+//
+// Implementation must support json.RawMessage and json.Marshaler/json.Unmarshaler
+// as encoding/json does, because messages keep params and results as raw JSON.
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// Decoder reads JSON values from a stream, it is implemented by *json.Decoder.
+//
+// If a value can not be decoded into target but decoder can continue with the next value,
+// Decode should return *ValueError (or *json.UnmarshalTypeError), so that only this value of batch
+// is responded with an error. Other errors stop decoding of batch.
+type Decoder interface {
+	Token() (json.Token, error)
+	More() bool
+	Decode(v interface{}) error
+}
+
+// ValueError is a decoding error of a single value, decoder can continue with the next value.
+type ValueError struct {
+	Err error
+}
+
+// Error returns error message.
+func (e *ValueError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns underlying error.
+func (e *ValueError) Unwrap() error {
+	return e.Err
+}
+
+// isValueError checks if decoder can continue after error.
+func isValueError(err error) bool {
+	var (
+		valueErr *ValueError
+		typeErr  *json.UnmarshalTypeError
+	)
+
+	return errors.As(err, &valueErr) || errors.As(err, &typeErr)
+}
+
+// StreamCodec is implemented by Codec that can decode batches as a stream.
+//
+// Batches are decoded with encoding/json if Codec does not implement StreamCodec.
+type StreamCodec interface {
+	NewDecoder(r io.Reader) Decoder
+}
+
+// JSONCodec implements Codec with encoding/json, it is used by default.
+type JSONCodec struct{}
+
+// Marshal encodes value.
+func (JSONCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal decodes value.
+func (JSONCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// NewDecoder creates stream decoder.
+func (JSONCodec) NewDecoder(r io.Reader) Decoder {
+	return json.NewDecoder(r)
+}
+
+func (h *Handler) codec() Codec {
+	if h.Codec != nil {
+		return h.Codec
+	}
+
+	return JSONCodec{}
+}
+
+func (h *Handler) newDecoder(r io.Reader) Decoder {
+	if sc, ok := h.codec().(StreamCodec); ok {
+		return sc.NewDecoder(r)
+	}
+
+	return json.NewDecoder(r)
+}
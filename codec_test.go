@@ -0,0 +1,260 @@
+package jsonrpc_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/jsonrpc"
+	"github.com/swaggest/usecase"
+)
+
+// countingCodec counts calls of Codec methods.
+type countingCodec struct {
+	jsonrpc.JSONCodec
+	marshal, unmarshal int64
+}
+
+func (c *countingCodec) Marshal(v interface{}) ([]byte, error) {
+	atomic.AddInt64(&c.marshal, 1)
+
+	return c.JSONCodec.Marshal(v)
+}
+
+func (c *countingCodec) Unmarshal(data []byte, v interface{}) error {
+	atomic.AddInt64(&c.unmarshal, 1)
+
+	return c.JSONCodec.Unmarshal(data, v)
+}
+
+// pooledCodec marshals with pooled buffers.
+type pooledCodec struct {
+	jsonrpc.JSONCodec
+	pool sync.Pool
+}
+
+func (c *pooledCodec) Marshal(v interface{}) ([]byte, error) {
+	buf, ok := c.pool.Get().(*bytes.Buffer)
+	if !ok {
+		buf = bytes.NewBuffer(nil)
+	}
+
+	defer c.pool.Put(buf)
+
+	buf.Reset()
+
+	if err := json.NewEncoder(buf).Encode(v); err != nil {
+		return nil, err
+	}
+
+	// Trimming new line added by encoder.
+	return append([]byte(nil), buf.Bytes()[:buf.Len()-1]...), nil
+}
+
+func TestHandler_Codec(t *testing.T) {
+	c := &countingCodec{}
+
+	h := &jsonrpc.Handler{}
+	h.Codec = c
+	h.Add(echoUseCase())
+
+	req, err := http.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(
+		`{"jsonrpc":"2.0","method":"echo","params":{"a":"abc","b":1},"id":1}`)))
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	assert.Equal(t, `{"jsonrpc":"2.0","result":{"b":1,"a":"abc"},"id":1}`, w.Body.String())
+	assert.Equal(t, int64(2), c.marshal)   // Result and response.
+	assert.Equal(t, int64(2), c.unmarshal) // Request and params.
+
+	req, err = http.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(
+		`{"jsonrpc":"2.0","method":"echo","params":["abc",1],"id":1}`)))
+	require.NoError(t, err)
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	assert.Equal(t, `{"jsonrpc":"2.0","result":{"b":1,"a":"abc"},"id":1}`, w.Body.String())
+	assert.Equal(t, int64(6), c.marshal)   // Names of two positional params, result and response.
+	assert.Equal(t, int64(5), c.unmarshal) // Request, positional params and params.
+}
+
+// strictCodec decodes streams with its own errors.
+type strictCodec struct {
+	jsonrpc.JSONCodec
+}
+
+func (strictCodec) NewDecoder(r io.Reader) jsonrpc.Decoder {
+	return strictDecoder{Decoder: json.NewDecoder(r)}
+}
+
+type strictDecoder struct {
+	*json.Decoder
+}
+
+func (d strictDecoder) Decode(v interface{}) error {
+	var raw json.RawMessage
+
+	if err := d.Decoder.Decode(&raw); err != nil {
+		return err
+	}
+
+	if len(raw) == 0 || raw[0] != '{' {
+		return &jsonrpc.ValueError{Err: errors.New("object expected")}
+	}
+
+	return json.Unmarshal(raw, v)
+}
+
+func TestHandler_ServeHTTP_streamCodec(t *testing.T) {
+	h := &jsonrpc.Handler{}
+	h.Codec = strictCodec{}
+	h.Add(echoUseCase())
+
+	req, err := http.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(
+		`[1,{"jsonrpc":"2.0","method":"echo","params":{"a":"abc","b":1},"id":1}]`)))
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	// Invalid element does not stop decoding of batch.
+	assert.Equal(t, `[{"jsonrpc":"2.0","error":{"code":-32600,"message":"failed to unmarshal request: object expected"},"id":null},`+
+		`{"jsonrpc":"2.0","result":{"b":1,"a":"abc"},"id":1}]`, w.Body.String())
+}
+
+func TestPeer_Notify_codec(t *testing.T) {
+	c := &countingCodec{}
+
+	u := usecase.NewIOI(nil, nil, func(ctx context.Context, input, output interface{}) error {
+		p, _ := jsonrpc.PeerFromContext(ctx)
+
+		return p.Notify("tick", 1)
+	})
+	u.SetName("tick")
+
+	h := &jsonrpc.Handler{}
+	h.Codec = c
+	h.Add(u)
+
+	srvConn, cliConn := net.Pipe()
+	done := make(chan error)
+
+	go func() {
+		done <- h.ServeConn(context.Background(), srvConn, jsonrpc.FramingNewline)
+	}()
+
+	_, err := cliConn.Write([]byte(`{"jsonrpc":"2.0","method":"tick"}` + "\n"))
+	require.NoError(t, err)
+
+	resp, err := bufio.NewReader(cliConn).ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, `{"jsonrpc":"2.0","method":"tick","params":1}`+"\n", resp)
+
+	require.NoError(t, cliConn.Close())
+	require.NoError(t, <-done)
+
+	assert.Equal(t, int64(3), atomic.LoadInt64(&c.marshal)) // Params and notification, result of call.
+}
+
+// benchCodecs are compared in benchmarks, add other implementations here to compare them with default.
+var benchCodecs = []struct {
+	name  string
+	codec jsonrpc.Codec
+}{
+	{name: "encoding_json", codec: jsonrpc.JSONCodec{}},
+	{name: "pooled_encoder", codec: &pooledCodec{}},
+}
+
+func BenchmarkHandler_ServeHTTP_codec(b *testing.B) {
+	batch := make([]string, 0, 100)
+	for i := 0; i < 100; i++ {
+		batch = append(batch, `{"jsonrpc":"2.0","method":"echo","params":{"a":"abc","b":1},"id":`+strconv.Itoa(i)+`}`)
+	}
+
+	bodies := []struct {
+		name string
+		body []byte
+	}{
+		{name: "single", body: []byte(`{"jsonrpc":"2.0","method":"echo","params":{"a":"abc","b":1},"id":1}`)},
+		{name: "batch", body: []byte("[" + strings.Join(batch, ",") + "]")},
+	}
+
+	for _, bc := range benchCodecs {
+		h := &jsonrpc.Handler{}
+		h.Codec = bc.codec
+		h.Add(echoUseCase())
+
+		for _, body := range bodies {
+			b.Run(bc.name+"/"+body.name, func(b *testing.B) {
+				b.ReportAllocs()
+				b.SetBytes(int64(len(body.body)))
+
+				for i := 0; i < b.N; i++ {
+					req, err := http.NewRequest(http.MethodPost, "/", bytes.NewReader(body.body))
+					if err != nil {
+						b.Fatal(err)
+					}
+
+					w := httptest.NewRecorder()
+					h.ServeHTTP(w, req)
+
+					if w.Code != http.StatusOK {
+						b.Fatal(w.Code)
+					}
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkCodec(b *testing.B) {
+	resp := jsonrpc.Response{
+		JSONRPC: "2.0",
+		Result:  json.RawMessage(`{"b":1,"a":"abc"}`),
+	}
+
+	data, err := json.Marshal(resp)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for _, bc := range benchCodecs {
+		b.Run(bc.name+"/marshal", func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				if _, err := bc.codec.Marshal(resp); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		b.Run(bc.name+"/unmarshal", func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				var r jsonrpc.Response
+
+				if err := bc.codec.Unmarshal(data, &r); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"sync"
+)
+
+// messageConn reads and writes JSON-RPC messages over a persistent connection.
+type messageConn interface {
+	// ReadMessage blocks until a complete message (single request or batch) is available.
+	ReadMessage() ([]byte, error)
+	WriteMessage(data []byte) error
+	Close() error
+}
+
+// serveConn reads messages from a persistent connection and dispatches them concurrently.
+//
+// Responses are written as soon as they are available, so they may be out of order.
+// On end of input in-flight calls are finished before returning, other read errors cancel them.
+// Handler.MaxConnInFlight limits number of messages that are processed at the same time.
+func (h *Handler) serveConn(ctx context.Context, mc messageConn) error {
+	ctx, cancel := context.WithCancel(ctx)
+
+	p := &Peer{
+		mc:    mc,
+		codec: h.codec(),
+	}
+
+	ctx = context.WithValue(ctx, peerCtxKey{}, p)
+
+	if !h.lifecycle.addConn(p) {
+		cancel()
+
+		return errShuttingDown
+	}
+
+	var (
+		wg  = sync.WaitGroup{}
+		eof bool
+	)
+
+	defer func() {
+		// Input is complete, in-flight calls can still send responses.
+		if eof {
+			wg.Wait()
+		}
+
+		cancel()
+		h.lifecycle.removeConn(p)
+		p.closeSubscriptions()
+		wg.Wait()
+	}()
+
+	var sem chan struct{}
+	if h.MaxConnInFlight > 0 {
+		sem = make(chan struct{}, h.MaxConnInFlight)
+	}
+
+	for {
+		if sem != nil {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		msg, err := mc.ReadMessage()
+		if err != nil {
+			// Connection is closed by Handler.Shutdown.
+			if h.lifecycle.isShutting() {
+				return nil
+			}
+
+			if errors.Is(err, io.EOF) {
+				eof = true
+
+				return nil
+			}
+
+			return err
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer func() {
+				if sem != nil {
+					<-sem
+				}
+
+				wg.Done()
+			}()
+
+			if resp := h.serve(ctx, bytes.NewReader(msg)); resp != nil {
+				if err := p.write(resp); err != nil {
+					// Broken connection, closing to stop reading.
+					_ = mc.Close() //nolint:errcheck // Write error is more relevant.
+				}
+			}
+		}()
+	}
+}
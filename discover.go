@@ -0,0 +1,64 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/swaggest/usecase"
+)
+
+// MethodDiscover is a name of service discovery method defined by OpenRPC.
+const MethodDiscover = "rpc.discover"
+
+var errNoServiceDocument = errors.New("service description is not available")
+
+// discoverUseCase returns service description from OpenRPC or OpenAPI collector.
+func (h *Handler) discoverUseCase() usecase.Interactor {
+	u := usecase.NewIOI(nil, new(json.RawMessage), func(ctx context.Context, input, output interface{}) error {
+		var (
+			doc []byte
+			err error
+		)
+
+		switch {
+		case h.OpenRPC != nil:
+			doc, err = h.OpenRPC.document()
+		case h.OpenAPI != nil:
+			doc, err = h.OpenAPI.document()
+		default:
+			return errNoServiceDocument
+		}
+
+		if err != nil {
+			return err
+		}
+
+		*output.(*json.RawMessage) = doc
+
+		return nil
+	})
+
+	u.SetName(MethodDiscover)
+	u.SetTitle("Service Discovery")
+	u.SetDescription("Returns service description document.")
+
+	return u
+}
+
+// method finds registered method by name.
+func (h *Handler) method(name string) (method, bool) {
+	h.methodsMu.RLock()
+	m, found := h.methods[name]
+	h.methodsMu.RUnlock()
+
+	if found || name != MethodDiscover || !h.EnableDiscover {
+		return m, found
+	}
+
+	h.discoverOnce.Do(func() {
+		h.discover = h.newMethod(h.discoverUseCase())
+	})
+
+	return h.discover, true
+}
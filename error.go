@@ -1,11 +1,80 @@
 package jsonrpc
 
 import (
+	"context"
+	"errors"
 	"sort"
 
 	"github.com/swaggest/usecase/status"
 )
 
+// Server error codes mapped from canonical status codes of use case errors.
+//
+// Status code is mapped to -32000 - status, for example status.NotFound (5) becomes -32005.
+// Statuses status.Unknown and status.Internal are mapped to CodeInternalError.
+const (
+	CodeCanceled           = ErrorCode(-32000 - status.Canceled)
+	CodeInvalidArgument    = ErrorCode(-32000 - status.InvalidArgument)
+	CodeDeadlineExceeded   = ErrorCode(-32000 - status.DeadlineExceeded)
+	CodeNotFound           = ErrorCode(-32000 - status.NotFound)
+	CodeAlreadyExists      = ErrorCode(-32000 - status.AlreadyExists)
+	CodePermissionDenied   = ErrorCode(-32000 - status.PermissionDenied)
+	CodeResourceExhausted  = ErrorCode(-32000 - status.ResourceExhausted)
+	CodeFailedPrecondition = ErrorCode(-32000 - status.FailedPrecondition)
+	CodeAborted            = ErrorCode(-32000 - status.Aborted)
+	CodeOutOfRange         = ErrorCode(-32000 - status.OutOfRange)
+	CodeUnimplemented      = ErrorCode(-32000 - status.Unimplemented)
+	CodeUnavailable        = ErrorCode(-32000 - status.Unavailable)
+	CodeDataLoss           = ErrorCode(-32000 - status.DataLoss)
+	CodeUnauthenticated    = ErrorCode(-32000 - status.Unauthenticated)
+)
+
+// StatusErrorCode returns JSON-RPC error code for canonical status code.
+func StatusErrorCode(st status.Code) ErrorCode {
+	switch st {
+	case status.OK, status.Unknown, status.Internal:
+		return CodeInternalError
+	default:
+		return ErrorCode(-32000 - st)
+	}
+}
+
+// errorCode returns JSON-RPC error code and message for use case error.
+//
+// Application error code takes precedence over canonical status, context errors
+// without canonical status are mapped to CodeDeadlineExceeded and CodeCanceled.
+func errorCode(err error) (ErrorCode, string) {
+	var (
+		code ErrorCode
+		msg  = "operation failed"
+
+		withStatus  ErrWithCanonicalStatus
+		withAppCode ErrWithAppCode
+	)
+
+	if errors.As(err, &withStatus) {
+		code = StatusErrorCode(withStatus.Status())
+
+		if code != CodeInternalError {
+			msg = withStatus.Status().Error()
+		}
+	} else if errors.Is(err, context.DeadlineExceeded) {
+		code, msg = CodeDeadlineExceeded, status.DeadlineExceeded.Error()
+	} else if errors.Is(err, context.Canceled) {
+		code, msg = CodeCanceled, status.Canceled.Error()
+	}
+
+	if errors.As(err, &withAppCode) && withAppCode.AppErrCode() != 0 {
+		code = ErrorCode(withAppCode.AppErrCode())
+	}
+
+	if code == 0 {
+		code = CodeInternalError
+	}
+
+	return code, msg
+}
+
 // ErrWithFields exposes structured context of error.
 type ErrWithFields interface {
 	error
@@ -24,6 +93,27 @@ type ErrWithCanonicalStatus interface {
 	Status() status.Code
 }
 
+// Error returns error message.
+func (e *Error) Error() string {
+	switch d := e.Data.(type) {
+	case string:
+		return e.Message + ": " + d
+	case structuredErrorData:
+		return e.Message + ": " + d.Message
+	default:
+		return e.Message
+	}
+}
+
+// Unwrap exposes structured data of error as ErrWithFields.
+func (e *Error) Unwrap() error {
+	if d, ok := e.Data.(structuredErrorData); ok {
+		return d
+	}
+
+	return nil
+}
+
 // ValidationErrors is a list of validation errors.
 //
 // Key is field position (e.g. "path:id" or "body"), value is a list of issues with the field.
@@ -0,0 +1,118 @@
+package jsonrpc
+
+import (
+	"errors"
+
+	"github.com/swaggest/jsonschema-go"
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+)
+
+// xErrors is an OpenAPI operation extension that lists errors of method.
+const xErrors = "x-jsonrpc-errors"
+
+// methodError describes an error that method may return.
+type methodError struct {
+	Code       ErrorCode          `json:"code"`
+	Message    string             `json:"message"`
+	DataSchema *jsonschema.Schema `json:"dataSchema,omitempty"`
+}
+
+type validationErrorData struct {
+	Message string           `json:"error"`
+	Context ValidationErrors `json:"context"`
+}
+
+// methodErrors returns errors that may be returned by use case.
+//
+// Errors are collected from usecase.HasExpectedErrors, invalid params error is added for use case with input,
+// authentication and authorization errors are added for use case with HasRequiredPermissions.
+// Expected error can be an *Error to document custom error code and message.
+func methodErrors(u usecase.Interactor, v Validator) ([]methodError, error) {
+	var (
+		res []methodError
+
+		hasInput          usecase.HasInputPort
+		hasExpectedErrors usecase.HasExpectedErrors
+		hasPermissions    HasRequiredPermissions
+	)
+
+	if usecase.As(u, &hasPermissions) {
+		for _, st := range []status.Code{status.Unauthenticated, status.PermissionDenied} {
+			d, err := errorDataSchema("")
+			if err != nil {
+				return nil, err
+			}
+
+			res = append(res, methodError{Code: StatusErrorCode(st), Message: st.Error(), DataSchema: d})
+		}
+	}
+
+	if usecase.As(u, &hasInput) && hasInput.InputPort() != nil {
+		var (
+			data interface{} = ""
+			msg              = "failed to unmarshal parameters"
+		)
+
+		if v != nil {
+			data = validationErrorData{}
+			msg = "invalid parameters"
+		}
+
+		d, err := errorDataSchema(data)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, methodError{Code: CodeInvalidParams, Message: msg, DataSchema: d})
+	}
+
+	if !usecase.As(u, &hasExpectedErrors) {
+		return res, nil
+	}
+
+	for _, e := range hasExpectedErrors.ExpectedErrors() {
+		var (
+			ed     methodError
+			data   interface{} = ""
+			rpcErr *Error
+			fields ErrWithFields
+		)
+
+		if errors.As(e, &rpcErr) {
+			ed.Code = rpcErr.Code
+			ed.Message = rpcErr.Message
+			data = rpcErr.Data
+		} else {
+			ed.Code, ed.Message = errorCode(e)
+
+			if errors.As(e, &fields) {
+				data = structuredErrorData{}
+			}
+		}
+
+		if data != nil {
+			d, err := errorDataSchema(data)
+			if err != nil {
+				return nil, err
+			}
+
+			ed.DataSchema = d
+		}
+
+		res = append(res, ed)
+	}
+
+	return res, nil
+}
+
+func errorDataSchema(data interface{}) (*jsonschema.Schema, error) {
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(data, jsonschema.InlineRefs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
@@ -5,9 +5,11 @@ go 1.18
 require (
 	github.com/bool64/dev v0.2.19
 	github.com/go-chi/chi/v5 v5.0.7
+	github.com/gorilla/websocket v1.5.0
 	github.com/santhosh-tekuri/jsonschema/v2 v2.2.0
 	github.com/stretchr/testify v1.8.0
 	github.com/swaggest/assertjson v1.7.0
+	github.com/swaggest/jsonschema-go v0.3.37
 	github.com/swaggest/openapi-go v0.2.20
 	github.com/swaggest/swgui v1.4.5
 	github.com/swaggest/usecase v1.1.3
@@ -19,7 +21,6 @@ require (
 	github.com/iancoleman/orderedmap v0.2.0 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/sergi/go-diff v1.2.0 // indirect
-	github.com/swaggest/jsonschema-go v0.3.37 // indirect
 	github.com/swaggest/refl v1.1.0 // indirect
 	github.com/yudai/gojsondiff v1.0.0 // indirect
 	github.com/yudai/golcs v0.0.0-20170316035057-ecda9a501e82 // indirect
@@ -0,0 +1,74 @@
+package jsonrpc
+
+import (
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/usecase"
+)
+
+// Group registers methods with common name prefix, middlewares and documentation annotations.
+type Group struct {
+	h           *Handler
+	prefix      string
+	middlewares []usecase.Middleware
+	openAPI     []func(op *openapi3.Operation) error
+	openRPC     []func(m *OpenRPCMethod) error
+}
+
+// Group creates a group of methods with name prefix (for example "admin.") and middlewares.
+//
+// Group middlewares are applied after Handler.Middlewares.
+func (h *Handler) Group(prefix string, mw ...usecase.Middleware) *Group {
+	return &Group{
+		h:           h,
+		prefix:      prefix,
+		middlewares: mw,
+	}
+}
+
+// Group creates a nested group that inherits prefix, middlewares and annotations of parent group.
+func (g *Group) Group(prefix string, mw ...usecase.Middleware) *Group {
+	ng := &Group{
+		h:      g.h,
+		prefix: g.prefix + prefix,
+	}
+
+	ng.middlewares = append(ng.middlewares, g.middlewares...)
+	ng.middlewares = append(ng.middlewares, mw...)
+	ng.openAPI = append(ng.openAPI, g.openAPI...)
+	ng.openRPC = append(ng.openRPC, g.openRPC...)
+
+	return ng
+}
+
+// Annotate adds OpenAPI operation configuration (for example tags or security) to methods added to group.
+func (g *Group) Annotate(setup ...func(op *openapi3.Operation) error) *Group {
+	g.openAPI = append(g.openAPI, setup...)
+
+	return g
+}
+
+// AnnotateOpenRPC adds OpenRPC method configuration to methods added to group.
+func (g *Group) AnnotateOpenRPC(setup ...func(m *OpenRPCMethod) error) *Group {
+	g.openRPC = append(g.openRPC, setup...)
+
+	return g
+}
+
+// Add registers use case interactor as JSON-RPC method with group prefix.
+//
+// Optional middlewares are applied to this method only, after group middlewares.
+func (g *Group) Add(u usecase.Interactor, mw ...usecase.Middleware) {
+	var withName usecase.HasName
+
+	if !usecase.As(u, &withName) {
+		panic("use case name is required")
+	}
+
+	name := g.prefix + withName.Name()
+
+	mws := make([]usecase.Middleware, 0, len(g.middlewares)+len(mw))
+	mws = append(mws, g.middlewares...)
+	mws = append(mws, mw...)
+
+	g.h.register(name, g.h.newMethod(u, mws...), g.openAPI, g.openRPC)
+}
@@ -0,0 +1,80 @@
+package jsonrpc_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/jsonrpc"
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/usecase"
+)
+
+func TestHandler_Group(t *testing.T) {
+	var trace []string
+
+	mw := func(name string) usecase.Middleware {
+		return usecase.MiddlewareFunc(func(next usecase.Interactor) usecase.Interactor {
+			return usecase.Interact(func(ctx context.Context, input, output interface{}) error {
+				trace = append(trace, name)
+
+				return next.Interact(ctx, input, output)
+			})
+		})
+	}
+
+	h := jsonrpc.Handler{}
+	h.OpenAPI = &jsonrpc.OpenAPI{}
+	h.Middlewares = append(h.Middlewares, mw("handler"))
+
+	h.Add(echoUseCase(), mw("method"))
+
+	admin := h.Group("admin.", mw("admin")).Annotate(func(op *openapi3.Operation) error {
+		op.Tags = append(op.Tags, "admin")
+
+		return nil
+	})
+	admin.Group("users.", mw("users")).Add(echoUseCase(), mw("method"))
+
+	call := func(method string) string {
+		trace = nil
+
+		req, err := http.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(
+			`{"jsonrpc":"2.0","method":"`+method+`","params":{"a":"abc","b":1},"id":1}`)))
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		return w.Body.String()
+	}
+
+	assert.Equal(t, `{"jsonrpc":"2.0","result":{"b":1,"a":"abc"},"id":1}`, call("echo"))
+	assert.Equal(t, []string{"handler", "method"}, trace)
+
+	assert.Equal(t, `{"jsonrpc":"2.0","result":{"b":1,"a":"abc"},"id":1}`, call("admin.users.echo"))
+	assert.Equal(t, []string{"handler", "admin", "users", "method"}, trace)
+
+	paths := h.OpenAPI.Reflector().SpecEns().Paths.MapOfPathItemValues
+	assert.Empty(t, paths["echo"].MapOfOperationValues["post"].Tags)
+	assert.Equal(t, []string{"admin"}, paths["admin.users.echo"].MapOfOperationValues["post"].Tags)
+	assert.Equal(t, "echo", *paths["echo"].MapOfOperationValues["post"].ID)
+	assert.Equal(t, "admin.users.echo", *paths["admin.users.echo"].MapOfOperationValues["post"].ID)
+
+	// Group annotations are not duplicated on re-add and do not outlive removed method.
+	admin.Group("users.").Add(echoUseCase())
+	assert.Equal(t, []string{"admin"}, paths["admin.users.echo"].MapOfOperationValues["post"].Tags)
+
+	h.Remove("admin.users.echo")
+
+	u := echoUseCase()
+	u.SetName("admin.users.echo")
+	h.Add(u)
+
+	paths = h.OpenAPI.Reflector().SpecEns().Paths.MapOfPathItemValues
+	assert.Empty(t, paths["admin.users.echo"].MapOfOperationValues["post"].Tags)
+}
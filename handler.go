@@ -1,7 +1,7 @@
 package jsonrpc
 
 import (
-	"bytes"
+	"bufio"
 	"context"
 	"encoding/json"
 	"errors"
@@ -10,8 +10,11 @@ import (
 	"net/http"
 	"reflect"
 	"sync"
+	"time"
 
+	"github.com/swaggest/openapi-go/openapi3"
 	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
 )
 
 // ErrorCode is an JSON-RPC 2.0 error code.
@@ -31,13 +34,100 @@ const ver = "2.0"
 // Handler serves JSON-RPC 2.0 methods with HTTP.
 type Handler struct {
 	OpenAPI     *OpenAPI
+	OpenRPC     *OpenRPC
 	Validator   Validator
 	Middlewares []usecase.Middleware
 
 	SkipParamsValidation bool
 	SkipResultValidation bool
 
-	methods map[string]method
+	// ErrorMapper optionally converts use case error to JSON-RPC error.
+	//
+	// If ErrorMapper is nil or returns nil, *Error is responded with its code, message and data,
+	// other errors have code defined by ErrWithAppCode or ErrWithCanonicalStatus (see StatusErrorCode)
+	// with fallback to CodeInternalError.
+	ErrorMapper func(err error) *Error
+
+	// EnableDiscover enables built-in rpc.discover method that returns
+	// OpenRPC (or OpenAPI if OpenRPC is not available) service description.
+	EnableDiscover bool
+
+	// OnPanic is called when method invocation panics, panic is recovered into CodeInternalError response.
+	OnPanic func(ctx context.Context, req Request, rcv interface{}, stack []byte)
+
+	// Debug enables exposing stack trace of recovered panic in error data.
+	Debug bool
+
+	// MaxBatchSize limits number of requests in a batch, larger batches are rejected with CodeInvalidRequest.
+	MaxBatchSize int
+
+	// BatchConcurrency limits number of concurrently executed requests of a single batch, unlimited by default.
+	BatchConcurrency int
+
+	// SequentialBatch enables execution of batch requests one by one in order of appearance.
+	SequentialBatch bool
+
+	// Authenticator optionally authenticates HTTP requests, principal is available with PrincipalFromContext.
+	//
+	// Methods that implement HasRequiredPermissions (see RequirePermissions) are rejected with
+	// CodeUnauthenticated or CodePermissionDenied if principal is missing or lacks permissions.
+	Authenticator Authenticator
+
+	// RateLimits limits calls by method name, limits are applied per client if RateLimitKey is set.
+	//
+	// Limited calls fail with CodeResourceExhausted and RateLimitError, Retry-After HTTP header
+	// is set if all calls of request are limited.
+	RateLimits map[string]RateLimit
+
+	// ClientRateLimit limits calls of a client to all methods, it requires RateLimitKey.
+	ClientRateLimit RateLimit
+
+	// RateLimitKey returns client key for rate limiting (see RateLimitByIP, RateLimitByPrincipal).
+	RateLimitKey func(ctx context.Context) string
+
+	// Timeout limits duration of method call, it can be overridden by method with HasTimeout (see WithTimeout).
+	// Calls that fail after deadline are responded with CodeDeadlineExceeded.
+	Timeout time.Duration
+
+	// NotificationWorkers enables background execution of HTTP notifications with a pool of workers,
+	// HTTP response 204 No Content is sent without waiting for notification execution.
+	//
+	// Notifications that do not fit NotificationQueueSize are rejected with CodeResourceExhausted,
+	// see also DrainNotifications.
+	NotificationWorkers int
+
+	// NotificationQueueSize limits number of notifications waiting for a worker, default NotificationWorkers.
+	NotificationQueueSize int
+
+	// Codec encodes and decodes messages, default JSONCodec.
+	Codec Codec
+
+	// MaxRequestSize limits size of HTTP request body or a message of persistent connection in bytes,
+	// larger requests are rejected with CodeInvalidRequest, unlimited by default.
+	MaxRequestSize int64
+
+	// MaxInFlight limits number of concurrently executed batch requests across all batches, unlimited by default.
+	MaxInFlight int
+
+	// MaxConnInFlight limits number of concurrently processed messages of a persistent connection,
+	// reading of the next message waits while the limit is reached, unlimited by default.
+	MaxConnInFlight int
+
+	// registryMu serializes changes of methods together with their documentation and validation schemas.
+	registryMu sync.Mutex
+
+	methodsMu sync.RWMutex
+	methods   map[string]method
+
+	discoverOnce sync.Once
+	discover     method
+
+	inFlightOnce sync.Once
+	inFlight     chan struct{}
+
+	limiter       rateLimiter
+	notifications notificationPool
+	lifecycle     lifecycle
 }
 
 type method struct {
@@ -49,6 +139,29 @@ type method struct {
 	inputBufferType reflect.Type
 	inputIsPtr      bool
 
+	// positional lists names of params by position.
+	positional []string
+
+	// headers lists input fields populated from HTTP request.
+	headers []headerField
+
+	// timeout overrides Handler.Timeout if not zero, negative value disables timeout.
+	timeout time.Duration
+
+	// target is a name of method if method is an alias.
+	target string
+
+	// paramsAdapter and resultAdapter optionally convert params and result of alias.
+	paramsAdapter func(params json.RawMessage) (json.RawMessage, error)
+	resultAdapter func(result json.RawMessage) (json.RawMessage, error)
+
+	// docs optionally replaces use case in documentation, validation schemas are still taken from use case.
+	docs usecase.Interactor
+
+	// permissions are required to call method if requiresAuth.
+	permissions  []string
+	requiresAuth bool
+
 	outputBufferType reflect.Type
 }
 
@@ -69,6 +182,55 @@ func (h *method) setupInputBuffer() {
 	}
 }
 
+func (h *method) setupPositionalParams() error {
+	h.positional = nil
+
+	if h.inputBufferType == nil || h.inputBufferType.Kind() != reflect.Struct {
+		return nil
+	}
+
+	names, err := positionalParams(h.inputBufferType)
+	if err != nil {
+		return err
+	}
+
+	h.positional = names
+
+	return nil
+}
+
+func (h *method) setupHeaders() error {
+	headers, err := headerFields(h.inputBufferType)
+	if err != nil {
+		return err
+	}
+
+	h.headers = headers
+
+	return nil
+}
+
+func (h *method) setupPermissions() {
+	var withPermissions HasRequiredPermissions
+
+	h.requiresAuth = usecase.As(h.useCase, &withPermissions)
+	h.permissions = nil
+
+	if h.requiresAuth {
+		h.permissions = withPermissions.RequiredPermissions()
+	}
+}
+
+func (h *method) setupTimeout() {
+	var withTimeout HasTimeout
+
+	h.timeout = 0
+
+	if usecase.As(h.useCase, &withTimeout) {
+		h.timeout = withTimeout.Timeout()
+	}
+}
+
 func (h *method) setupOutputBuffer() {
 	h.outputBufferType = nil
 
@@ -88,23 +250,122 @@ func (h *method) setupOutputBuffer() {
 type errCtxKey struct{}
 
 // Add registers use case interactor as JSON-RPC method.
-func (h *Handler) Add(u usecase.Interactor) {
+//
+// Methods can be added while Handler is serving, method with the same name is replaced.
+// Optional middlewares are applied to this method only, after Handler.Middlewares.
+func (h *Handler) Add(u usecase.Interactor, mw ...usecase.Middleware) {
+	var withName usecase.HasName
+
+	if !usecase.As(u, &withName) {
+		panic("use case name is required")
+	}
+
+	h.add(withName.Name(), u, mw)
+}
+
+// add registers use case interactor with method name.
+func (h *Handler) add(name string, u usecase.Interactor, mw []usecase.Middleware) {
+	h.register(name, h.newMethod(u, mw...), nil, nil)
+}
+
+// register adds method with name to registry and documentation.
+//
+// Previously registered method with the same name is replaced together with its validation schemas.
+func (h *Handler) register(
+	name string,
+	m method,
+	openAPIAnnotations []func(op *openapi3.Operation) error,
+	openRPCAnnotations []func(m *OpenRPCMethod) error,
+) {
+	h.registryMu.Lock()
+	defer h.registryMu.Unlock()
+
+	var (
+		v       Validator
+		schemas *pendingSchemas
+	)
+
+	// Schemas are applied to Validator together with method registration.
+	if h.Validator != nil {
+		schemas = &pendingSchemas{Validator: h.Validator}
+		v = schemas
+	}
+
+	u := m.useCase
+
+	if m.docs != nil {
+		// Validation schemas are collected from use case, documentation is then overwritten.
+		if v != nil {
+			h.collect(name, m, u, v, nil, nil)
+			v = skipSchemas{Validator: v}
+		}
+
+		u = m.docs
+	}
+
+	h.collect(name, m, u, v, openAPIAnnotations, openRPCAnnotations)
+
+	h.methodsMu.Lock()
+	defer h.methodsMu.Unlock()
+
+	if schemas != nil {
+		if err := schemas.apply(name); err != nil {
+			panic(fmt.Sprintf("failed to add validation schemas: %s", err.Error()))
+		}
+	}
+
 	if h.methods == nil {
 		h.methods = make(map[string]method)
 	}
 
-	var withName usecase.HasName
+	h.methods[name] = m
+}
 
-	if !usecase.As(u, &withName) {
-		panic("use case name is required")
+// collect adds use case to documentation and provides validation schemas.
+func (h *Handler) collect(
+	name string,
+	m method,
+	u usecase.Interactor,
+	v Validator,
+	openAPIAnnotations []func(op *openapi3.Operation) error,
+	openRPCAnnotations []func(m *OpenRPCMethod) error,
+) {
+	if h.OpenAPI != nil {
+		annotations := append(h.securityAnnotations(m), openAPIAnnotations...)
+
+		err := h.OpenAPI.Collect(name, u, v, annotations...)
+		if err != nil {
+			panic(fmt.Sprintf("failed to add to OpenAPI schema: %s", err.Error()))
+		}
 	}
 
+	if h.OpenRPC != nil {
+		v := v
+
+		// Validation schemas are already provided by OpenAPI.
+		if h.OpenAPI != nil && v != nil {
+			v = skipSchemas{Validator: v}
+		}
+
+		err := h.OpenRPC.Collect(name, u, v, openRPCAnnotations...)
+		if err != nil {
+			panic(fmt.Sprintf("failed to add to OpenRPC schema: %s", err.Error()))
+		}
+	}
+}
+
+// newMethod wraps use case with middlewares and prepares buffers.
+func (h *Handler) newMethod(u usecase.Interactor, mw ...usecase.Middleware) method {
 	var fu usecase.Interactor = usecase.Interact(func(ctx context.Context, input, output interface{}) error {
 		return ctx.Value(errCtxKey{}).(error)
 	})
 
-	u = usecase.Wrap(u, h.Middlewares...)
-	fu = usecase.Wrap(fu, h.Middlewares...)
+	mws := make([]usecase.Middleware, 0, len(h.Middlewares)+len(mw))
+	mws = append(mws, h.Middlewares...)
+	mws = append(mws, mw...)
+
+	u = usecase.Wrap(u, mws...)
+	fu = usecase.Wrap(fu, mws...)
 
 	m := method{
 		useCase:        u,
@@ -112,15 +373,18 @@ func (h *Handler) Add(u usecase.Interactor) {
 	}
 	m.setupInputBuffer()
 	m.setupOutputBuffer()
+	m.setupPermissions()
+	m.setupTimeout()
 
-	h.methods[withName.Name()] = m
+	if err := m.setupPositionalParams(); err != nil {
+		panic(fmt.Sprintf("failed to setup positional parameters: %s", err.Error()))
+	}
 
-	if h.OpenAPI != nil {
-		err := h.OpenAPI.Collect(withName.Name(), u, h.Validator)
-		if err != nil {
-			panic(fmt.Sprintf("failed to add to OpenAPI schema: %s", err.Error()))
-		}
+	if err := m.setupHeaders(); err != nil {
+		panic(fmt.Sprintf("failed to setup header parameters: %s", err.Error()))
 	}
+
+	return m
 }
 
 // Request is an JSON-RPC request item.
@@ -146,134 +410,187 @@ type Error struct {
 	Data    interface{} `json:"data,omitempty"`
 }
 
-var errEmptyBody = errors.New("empty body")
+var (
+	errEmptyBody       = errors.New("empty body")
+	errRequestTooLarge = errors.New("request is too large")
+)
 
 func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json; charset: utf-8")
 
-	reqBody, err := io.ReadAll(r.Body)
-	if err != nil {
-		h.fail(w, fmt.Errorf("failed to read request body: %w", err), CodeParseError)
+	var body io.Reader = r.Body
 
-		return
+	if h.MaxRequestSize > 0 {
+		if r.ContentLength > h.MaxRequestSize {
+			h.fail(w, errRequestTooLarge, CodeInvalidRequest)
+
+			return
+		}
+
+		body = &limitedReader{r: body, n: h.MaxRequestSize}
 	}
 
-	reqBody = bytes.TrimLeft(reqBody, " \t\r\n")
-	if len(reqBody) == 0 {
-		h.fail(w, errEmptyBody, CodeParseError)
+	ctx := h.withHTTPRequest(r.Context(), r)
 
-		return
+	var hl *httpLimit
+
+	if h.RateLimits != nil || h.ClientRateLimit.Rate > 0 {
+		hl = &httpLimit{}
+		ctx = context.WithValue(ctx, httpLimitCtxKey{}, hl)
 	}
 
-	ctx := r.Context()
+	data := h.serve(ctx, body)
 
-	if reqBody[0] == '[' {
-		h.serveBatch(ctx, w, reqBody)
+	if hl != nil {
+		hl.setHeader(w)
+	}
+
+	if data == nil {
+		// Notification is accepted for background execution, synchronous notification has empty 200 OK response.
+		if h.NotificationWorkers > 0 {
+			w.WriteHeader(http.StatusNoContent)
+		}
 
 		return
 	}
 
+	if _, err := w.Write(data); err != nil {
+		h.fail(w, err, CodeInternalError)
+	}
+}
+
+// serve handles JSON-RPC request or batch and returns encoded response, nil is returned if there is nothing to reply.
+func (h *Handler) serve(ctx context.Context, r io.Reader) []byte {
+	br := bufio.NewReader(r)
+
+	// Skipping leading whitespace to detect batch.
+	for {
+		c, err := br.ReadByte()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return h.failure(errEmptyBody, CodeParseError)
+			}
+
+			return h.readFailure(err, CodeParseError)
+		}
+
+		if c != ' ' && c != '\t' && c != '\r' && c != '\n' {
+			_ = br.UnreadByte() //nolint:errcheck // Byte was just read.
+
+			if c == '[' {
+				return h.serveBatch(ctx, br)
+			}
+
+			break
+		}
+	}
+
+	reqBody, err := io.ReadAll(br)
+	if err != nil {
+		return h.readFailure(err, CodeParseError)
+	}
+
 	var (
 		req  Request
 		resp Response
 	)
 
-	if err := json.Unmarshal(reqBody, &req); err != nil {
-		h.fail(w, fmt.Errorf("failed to unmarshal request: %w", err), CodeParseError)
-
-		return
+	if err := h.codec().Unmarshal(reqBody, &req); err != nil {
+		return h.failure(fmt.Errorf("failed to unmarshal request: %w", err), CodeParseError)
 	}
 
 	resp.ID = req.ID
 	resp.JSONRPC = ver
 
 	if req.JSONRPC != ver {
-		h.fail(w, fmt.Errorf("invalid jsonrpc value: %q", req.JSONRPC), CodeInvalidRequest)
+		return h.failure(fmt.Errorf("invalid jsonrpc value: %q", req.JSONRPC), CodeInvalidRequest)
+	}
 
-		return
+	if h.isAsyncNotification(ctx, req) {
+		if code, err := h.enqueueNotification(ctx, req); err != nil {
+			return h.failure(err, code)
+		}
+
+		return nil
 	}
 
 	h.invoke(ctx, req, &resp)
 
 	if req.ID == nil {
-		return
+		return nil
 	}
 
-	data, err := json.Marshal(resp)
+	data, err := h.codec().Marshal(resp)
 	if err != nil {
-		h.fail(w, err, CodeInternalError)
-
-		return
+		return h.failure(err, CodeInternalError)
 	}
 
-	if _, err := w.Write(data); err != nil {
-		h.fail(w, err, CodeInternalError)
-	}
+	return data
 }
 
-func (h *Handler) serveBatch(ctx context.Context, w http.ResponseWriter, reqBody []byte) {
-	var reqs []Request
-	if err := json.Unmarshal(reqBody, &reqs); err != nil {
-		h.fail(w, fmt.Errorf("failed to unmarshal request: %w", err), CodeInvalidRequest)
+type structuredErrorData struct {
+	Message string                 `json:"error"`
+	Context map[string]interface{} `json:"context"`
+}
 
-		return
-	}
+// Error returns error message.
+func (d structuredErrorData) Error() string {
+	return d.Message
+}
 
-	wg := sync.WaitGroup{}
-	wg.Add(len(reqs))
+// Fields returns structured context of error.
+func (d structuredErrorData) Fields() map[string]interface{} {
+	return d.Context
+}
 
-	resps := make([]*Response, 0, len(reqs))
+func (h *Handler) invoke(ctx context.Context, req Request, resp *Response) {
+	if req.Method == MethodCancelRequest {
+		if p, ok := PeerFromContext(ctx); ok {
+			h.cancelRequest(p, req, resp)
 
-	for _, req := range reqs {
-		req := req
-		resp := Response{
-			JSONRPC: ver,
+			return
 		}
+	}
 
-		if req.ID != nil {
-			resp.ID = req.ID
-			resps = append(resps, &resp)
-		}
+	ctx, end, ok := h.lifecycle.begin(ctx)
+	if !ok {
+		resp.Error = &Error{Code: CodeShuttingDown, Message: errShuttingDown.Error()}
 
-		if req.JSONRPC != ver {
-			resp.Error = &Error{
-				Code:    CodeInvalidRequest,
-				Message: fmt.Sprintf("invalid jsonrpc value: %q", req.JSONRPC),
-			}
+		return
+	}
 
-			continue
-		}
+	defer end()
 
-		go func() {
-			defer wg.Done()
+	h.call(ctx, req, resp)
+}
 
-			h.invoke(ctx, req, &resp)
-		}()
-	}
+// call executes method, it is tracked by invoke.
+func (h *Handler) call(ctx context.Context, req Request, resp *Response) {
+	var input, output interface{}
 
-	wg.Wait()
+	ctx = withMeta(ctx, req)
 
-	data, err := json.Marshal(resps)
-	if err != nil {
-		h.fail(w, err, CodeInternalError)
+	defer func() {
+		if rcv := recover(); rcv != nil {
+			h.recovered(ctx, req, resp, rcv)
+		}
+	}()
 
-		return
-	}
+	if h.RateLimits != nil || h.ClientRateLimit.Rate > 0 {
+		err := h.rateLimit(ctx, req.Method)
 
-	if _, err := w.Write(data); err != nil {
-		h.fail(w, err, CodeInternalError)
-	}
-}
+		if hl, ok := ctx.Value(httpLimitCtxKey{}).(*httpLimit); ok {
+			hl.add(err)
+		}
 
-type structuredErrorData struct {
-	Error   string                 `json:"error"`
-	Context map[string]interface{} `json:"context"`
-}
+		if err != nil {
+			h.interactErr(resp, err)
 
-func (h *Handler) invoke(ctx context.Context, req Request, resp *Response) {
-	var input, output interface{}
+			return
+		}
+	}
 
-	m, found := h.methods[req.Method]
+	m, found := h.method(req.Method)
 	if !found {
 		resp.Error = &Error{
 			Code:    CodeMethodNotFound,
@@ -283,6 +600,18 @@ func (h *Handler) invoke(ctx context.Context, req Request, resp *Response) {
 		return
 	}
 
+	if m.target != "" {
+		ctx = withAlias(ctx, m.target)
+	}
+
+	if m.requiresAuth {
+		if err := authorize(ctx, m.permissions); err != nil {
+			h.interactErr(resp, err)
+
+			return
+		}
+	}
+
 	if m.inputBufferType != nil {
 		iv := reflect.New(m.inputBufferType)
 		input = iv.Interface()
@@ -300,8 +629,25 @@ func (h *Handler) invoke(ctx context.Context, req Request, resp *Response) {
 		output = reflect.New(m.outputBufferType).Interface()
 	}
 
-	if err := m.useCase.Interact(ctx, input, output); err != nil {
-		h.errResp(resp, "operation failed", CodeInternalError, err)
+	ictx, cancel := h.withTimeout(ctx, m)
+	defer cancel()
+
+	cancelled, err := h.interact(ictx, m, req, input, output)
+	if cancelled && err != nil {
+		resp.Error = &Error{Code: CodeRequestCancelled, Message: "request cancelled"}
+
+		return
+	}
+
+	// Any failure after deadline is a timeout, even if use case does not wrap context error.
+	if err != nil && errors.Is(ictx.Err(), context.DeadlineExceeded) {
+		h.errResp(resp, status.DeadlineExceeded.Error(), CodeDeadlineExceeded, err)
+
+		return
+	}
+
+	if err != nil {
+		h.interactErr(resp, err)
 
 		return
 	}
@@ -309,8 +655,25 @@ func (h *Handler) invoke(ctx context.Context, req Request, resp *Response) {
 	h.encode(ctx, m, req, resp, output)
 }
 
+// interact invokes use case as an in-flight call that can be cancelled by client.
+func (h *Handler) interact(
+	ctx context.Context,
+	m method,
+	req Request,
+	input, output interface{},
+) (cancelled bool, err error) {
+	ctx, release := trackCall(ctx, req)
+
+	// Call is released even if use case panics.
+	defer func() {
+		cancelled = release()
+	}()
+
+	return false, m.useCase.Interact(ctx, input, output)
+}
+
 func (h *Handler) encode(ctx context.Context, m method, req Request, resp *Response, output interface{}) {
-	data, err := json.Marshal(output)
+	data, err := h.codec().Marshal(output)
 	if err != nil {
 		resp.Error = &Error{
 			Code:    CodeInternalError,
@@ -332,11 +695,49 @@ func (h *Handler) encode(ctx context.Context, m method, req Request, resp *Respo
 		}
 	}
 
+	if m.resultAdapter != nil {
+		if data, err = m.resultAdapter(data); err != nil {
+			h.errResp(resp, "failed to adapt result", CodeInternalError, err)
+
+			return
+		}
+	}
+
 	resp.Result = data
 }
 
 func (h *Handler) decode(ctx context.Context, m method, req Request, resp *Response, input interface{}) bool {
-	if err := json.Unmarshal(req.Params, input); err != nil {
+	if m.paramsAdapter != nil {
+		params, err := m.paramsAdapter(req.Params)
+		if err != nil {
+			if m.failingUseCase != nil {
+				err = m.failingUseCase.Interact(context.WithValue(ctx, errCtxKey{}, err), nil, nil)
+			}
+
+			h.errResp(resp, "failed to adapt parameters", CodeInvalidParams, err)
+
+			return false
+		}
+
+		req.Params = params
+	}
+
+	if m.positional != nil && isPositional(req.Params) {
+		params, err := namedParams(h.codec(), req.Params, m.positional)
+		if err != nil {
+			if m.failingUseCase != nil {
+				err = m.failingUseCase.Interact(context.WithValue(ctx, errCtxKey{}, err), nil, nil)
+			}
+
+			h.errResp(resp, "failed to unmarshal parameters", CodeInvalidParams, err)
+
+			return false
+		}
+
+		req.Params = params
+	}
+
+	if err := h.codec().Unmarshal(req.Params, input); err != nil {
 		if m.failingUseCase != nil {
 			err = m.failingUseCase.Interact(context.WithValue(ctx, errCtxKey{}, err), nil, nil)
 		}
@@ -346,6 +747,18 @@ func (h *Handler) decode(ctx context.Context, m method, req Request, resp *Respo
 		return false
 	}
 
+	if m.headers != nil {
+		if err := decodeHeaders(ctx, m.headers, input); err != nil {
+			if m.failingUseCase != nil {
+				err = m.failingUseCase.Interact(context.WithValue(ctx, errCtxKey{}, err), nil, nil)
+			}
+
+			h.errResp(resp, "failed to unmarshal parameters", CodeInvalidParams, err)
+
+			return false
+		}
+	}
+
 	if h.Validator != nil && !h.SkipParamsValidation {
 		if err := h.Validator.ValidateParams(req.Method, req.Params); err != nil {
 			if m.failingUseCase != nil {
@@ -361,6 +774,29 @@ func (h *Handler) decode(ctx context.Context, m method, req Request, resp *Respo
 	return true
 }
 
+func (h *Handler) interactErr(resp *Response, err error) {
+	if h.ErrorMapper != nil {
+		if e := h.ErrorMapper(err); e != nil {
+			resp.Error = e
+
+			return
+		}
+	}
+
+	// JSON-RPC error is responded as is, for example an expected error of use case.
+	var rpcErr *Error
+	if errors.As(err, &rpcErr) {
+		e := *rpcErr
+		resp.Error = &e
+
+		return
+	}
+
+	code, msg := errorCode(err)
+
+	h.errResp(resp, msg, code, err)
+}
+
 func (h *Handler) errResp(resp *Response, msg string, code ErrorCode, err error) {
 	resp.Error = &Error{
 		Code:    code,
@@ -370,7 +806,7 @@ func (h *Handler) errResp(resp *Response, msg string, code ErrorCode, err error)
 	var se ErrWithFields
 	if errors.As(err, &se) {
 		resp.Error.Data = structuredErrorData{
-			Error:   se.Error(),
+			Message: se.Error(),
 			Context: se.Fields(),
 		}
 	} else if err != nil {
@@ -379,6 +815,32 @@ func (h *Handler) errResp(resp *Response, msg string, code ErrorCode, err error)
 }
 
 func (h *Handler) fail(w http.ResponseWriter, err error, code ErrorCode) {
+	_, err = w.Write(h.failure(err, code))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+}
+
+// readError returns error of reading or decoding request.
+func (h *Handler) readError(err error, code ErrorCode) *Error {
+	if errors.Is(err, errRequestTooLarge) {
+		return &Error{Code: CodeInvalidRequest, Message: errRequestTooLarge.Error()}
+	}
+
+	return &Error{Code: code, Message: fmt.Sprintf("failed to unmarshal request: %s", err.Error())}
+}
+
+// readFailure returns encoded error response of reading or decoding request.
+func (h *Handler) readFailure(err error, code ErrorCode) []byte {
+	e := h.readError(err, code)
+
+	return h.failure(errors.New(e.Message), e.Code)
+}
+
+// failure returns encoded error response.
+func (h *Handler) failure(err error, code ErrorCode) []byte {
 	resp := Response{
 		JSONRPC: ver,
 		Error: &Error{
@@ -387,17 +849,10 @@ func (h *Handler) fail(w http.ResponseWriter, err error, code ErrorCode) {
 		},
 	}
 
-	data, err := json.Marshal(resp)
+	data, err := h.codec().Marshal(resp)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-
-		return
+		return []byte(`{"jsonrpc":"2.0","error":{"code":-32603,"message":"failed to marshal error"},"id":null}`)
 	}
 
-	_, err = w.Write(data)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-
-		return
-	}
+	return data
 }
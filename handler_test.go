@@ -3,14 +3,21 @@ package jsonrpc_test
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/swaggest/jsonrpc"
 	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
 )
 
 func TestHandler_Add(t *testing.T) {
@@ -77,3 +84,389 @@ func TestHandler_Add(t *testing.T) {
 	assert.Equal(t, `{"jsonrpc":"2.0","error":{"code":-32602,"message":"invalid parameters","data":{"error":"validation failed","context":{"params":["#/a: length must be \u003e= 3, but got 1","#/b: must be \u003c= 8 but found 9","#: validation failed"]}}},"id":1}`, w.Body.String())
 	assert.Equal(t, 3, cnt)
 }
+
+func TestHandler_ServeHTTP_discover(t *testing.T) {
+	h := jsonrpc.Handler{}
+	h.OpenRPC = &jsonrpc.OpenRPC{}
+	h.OpenRPC.SpecEns().Info.Title = "Discovery"
+	h.EnableDiscover = true
+
+	u := usecase.NewIOI(nil, nil, func(ctx context.Context, input, output interface{}) error {
+		return nil
+	})
+	u.SetName("ping")
+	u.SetTitle("Ping")
+
+	h.Add(u)
+
+	req, err := http.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(`{"jsonrpc":"2.0","method":"rpc.discover","id":1}`)))
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	assert.Equal(t, `{"jsonrpc":"2.0","result":{"openrpc":"1.2.6","info":{"title":"Discovery","version":""},"methods":[{"name":"ping","summary":"Ping","params":[]}]},"id":1}`, w.Body.String())
+
+	h.EnableDiscover = false
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(`{"jsonrpc":"2.0","method":"rpc.discover","id":1}`)))
+	require.NoError(t, err)
+	h.ServeHTTP(w, req)
+	assert.Equal(t, `{"jsonrpc":"2.0","error":{"code":-32601,"message":"method not found: rpc.discover"},"id":1}`, w.Body.String())
+}
+
+func TestHandler_ServeHTTP_positional(t *testing.T) {
+	h := jsonrpc.Handler{}
+	h.OpenAPI = &jsonrpc.OpenAPI{}
+	h.Validator = &jsonrpc.JSONSchemaValidator{}
+
+	type inp struct {
+		Name   string `json:"name" minLength:"2" rpcpos:"1"`
+		Repeat int    `json:"repeat" rpcpos:"0"`
+		Skip   bool   `json:"skip"`
+	}
+
+	u := usecase.NewInteractor(func(ctx context.Context, input inp, output *[]string) error {
+		for i := 0; i < input.Repeat; i++ {
+			*output = append(*output, input.Name)
+		}
+
+		return nil
+	})
+	u.SetName("repeat")
+
+	h.Add(u)
+
+	for _, tc := range []struct {
+		params string
+		resp   string
+	}{
+		{
+			params: `[2, "ab"]`,
+			resp:   `{"jsonrpc":"2.0","result":["ab","ab"],"id":1}`,
+		},
+		{
+			params: `{"repeat":1,"name":"ab"}`,
+			resp:   `{"jsonrpc":"2.0","result":["ab"],"id":1}`,
+		},
+		{
+			params: `[1, "a"]`,
+			resp:   `{"jsonrpc":"2.0","error":{"code":-32602,"message":"invalid parameters","data":{"error":"validation failed","context":{"params":["#/name: length must be \u003e= 2, but got 1"]}}},"id":1}`,
+		},
+		{
+			params: `[1, "ab", true]`,
+			resp:   `{"jsonrpc":"2.0","error":{"code":-32602,"message":"failed to unmarshal parameters","data":"too many positional parameters: 3, expected at most 2"},"id":1}`,
+		},
+	} {
+		req, err := http.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(`{"jsonrpc":"2.0","method":"repeat","params":`+tc.params+`,"id":1}`)))
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		assert.Equal(t, tc.resp, w.Body.String(), tc.params)
+	}
+}
+
+func TestHandler_ServeHTTP_errorCodes(t *testing.T) {
+	var err error
+
+	h := jsonrpc.Handler{}
+
+	u := usecase.NewIOI(nil, nil, func(ctx context.Context, input, output interface{}) error {
+		return err
+	})
+	u.SetName("fail")
+
+	h.Add(u)
+
+	call := func() string {
+		req, rerr := http.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(`{"jsonrpc":"2.0","method":"fail","id":1}`)))
+		require.NoError(t, rerr)
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		return w.Body.String()
+	}
+
+	err = errors.New("failed")
+	assert.Equal(t, `{"jsonrpc":"2.0","error":{"code":-32603,"message":"operation failed","data":"failed"},"id":1}`, call())
+
+	err = status.Wrap(errors.New("no such user"), status.NotFound)
+	assert.Equal(t, `{"jsonrpc":"2.0","error":{"code":-32005,"message":"not found","data":"not found: no such user"},"id":1}`, call())
+
+	err = status.PermissionDenied
+	assert.Equal(t, `{"jsonrpc":"2.0","error":{"code":-32007,"message":"permission denied","data":"permission denied"},"id":1}`, call())
+
+	err = status.Wrap(errors.New("oops"), status.Internal)
+	assert.Equal(t, `{"jsonrpc":"2.0","error":{"code":-32603,"message":"operation failed","data":"internal: oops"},"id":1}`, call())
+
+	err = usecase.Error{
+		AppCode:    1001,
+		StatusCode: status.AlreadyExists,
+		Value:      errors.New("duplicate user"),
+		Context:    map[string]interface{}{"user": "foo"},
+	}
+	assert.Equal(t, `{"jsonrpc":"2.0","error":{"code":1001,"message":"already exists","data":{"error":"already exists: duplicate user","context":{"user":"foo"}}},"id":1}`, call())
+
+	// JSON-RPC error is responded as is.
+	err = fmt.Errorf("check length: %w", &jsonrpc.Error{Code: 1001, Message: "too long", Data: 10})
+	assert.Equal(t, `{"jsonrpc":"2.0","error":{"code":1001,"message":"too long","data":10},"id":1}`, call())
+
+	h.ErrorMapper = func(err error) *jsonrpc.Error {
+		if errors.Is(err, status.NotFound) {
+			return &jsonrpc.Error{Code: 404, Message: "missing"}
+		}
+
+		return nil
+	}
+
+	err = status.NotFound
+	assert.Equal(t, `{"jsonrpc":"2.0","error":{"code":404,"message":"missing"},"id":1}`, call())
+
+	err = errors.New("failed")
+	assert.Equal(t, `{"jsonrpc":"2.0","error":{"code":-32603,"message":"operation failed","data":"failed"},"id":1}`, call())
+}
+
+func TestHandler_ServeHTTP_panic(t *testing.T) {
+	var (
+		panicked interface{}
+		method   string
+	)
+
+	h := jsonrpc.Handler{}
+	h.OnPanic = func(ctx context.Context, req jsonrpc.Request, rcv interface{}, stack []byte) {
+		panicked = rcv
+		method = req.Method
+
+		assert.Contains(t, string(stack), "TestHandler_ServeHTTP_panic")
+	}
+
+	u := usecase.NewIOI(nil, nil, func(ctx context.Context, input, output interface{}) error {
+		panic("oops")
+	})
+	u.SetName("panic")
+
+	h.Add(u)
+	h.Add(echoUseCase())
+
+	req, err := http.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(`[
+		{"jsonrpc":"2.0","method":"panic","id":1},
+		{"jsonrpc":"2.0","method":"echo","params":{"a":"abc","b":1},"id":2}
+	]`)))
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	assert.Equal(t, `[{"jsonrpc":"2.0","error":{"code":-32603,"message":"internal error"},"id":1},{"jsonrpc":"2.0","result":{"b":1,"a":"abc"},"id":2}]`, w.Body.String())
+	assert.Equal(t, "oops", panicked)
+	assert.Equal(t, "panic", method)
+
+	h.Debug = true
+
+	req, err = http.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(`{"jsonrpc":"2.0","method":"panic","id":1}`)))
+	require.NoError(t, err)
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	var resp jsonrpc.Response
+
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotNil(t, resp.Error)
+
+	data, ok := resp.Error.Data.(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, "panic: oops", data["error"])
+	assert.NotEmpty(t, data["context"].(map[string]interface{})["stack"])
+}
+
+func TestHandler_ServeHTTP_batchLimits(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		calls   []int
+		running int
+		peak    int
+	)
+
+	h := jsonrpc.Handler{}
+	h.MaxBatchSize = 3
+
+	u := usecase.NewIOI(new(int), nil, func(ctx context.Context, input, output interface{}) error {
+		mu.Lock()
+		calls = append(calls, *input.(*int))
+		running++
+
+		if running > peak {
+			peak = running
+		}
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+
+		return nil
+	})
+	u.SetName("work")
+
+	h.Add(u)
+
+	serve := func(body string) string {
+		req, err := http.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(body)))
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		return w.Body.String()
+	}
+
+	assert.Equal(t, `{"jsonrpc":"2.0","error":{"code":-32600,"message":"batch is too large, max 3"},"id":null}`, serve(`[
+		{"jsonrpc":"2.0","method":"work","params":1,"id":1},
+		{"jsonrpc":"2.0","method":"work","params":2,"id":2},
+		{"jsonrpc":"2.0","method":"work","params":3,"id":3},
+		{"jsonrpc":"2.0","method":"work","params":4,"id":4}
+	]`))
+	assert.Empty(t, calls)
+
+	batch := `[
+		{"jsonrpc":"2.0","method":"work","params":1,"id":1},
+		{"jsonrpc":"2.0","method":"work","params":2},
+		{"jsonrpc":"2.0","method":"work","params":3,"id":3}
+	]`
+
+	h.BatchConcurrency = 2
+
+	assert.Equal(t, `[{"jsonrpc":"2.0","result":null,"id":1},{"jsonrpc":"2.0","result":null,"id":3}]`, serve(batch))
+	assert.Len(t, calls, 3)
+	assert.LessOrEqual(t, peak, 2)
+
+	calls = nil
+	peak = 0
+	h.SequentialBatch = true
+
+	assert.Equal(t, `[{"jsonrpc":"2.0","result":null,"id":1},{"jsonrpc":"2.0","result":null,"id":3}]`, serve(batch))
+	assert.Equal(t, []int{1, 2, 3}, calls)
+	assert.Equal(t, 1, peak)
+}
+
+func TestHandler_ServeHTTP_maxRequestSize(t *testing.T) {
+	h := jsonrpc.Handler{}
+	h.MaxRequestSize = 100
+	h.Add(echoUseCase())
+
+	serve := func(body string) string {
+		req, err := http.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(body)))
+		require.NoError(t, err)
+
+		req.ContentLength = -1 // Unknown length to read body up to the limit.
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		return w.Body.String()
+	}
+
+	assert.Equal(t, `{"jsonrpc":"2.0","result":{"b":1,"a":"abc"},"id":1}`,
+		serve(`{"jsonrpc":"2.0","method":"echo","params":{"a":"abc","b":1},"id":1}`))
+
+	assert.Equal(t, `{"jsonrpc":"2.0","error":{"code":-32600,"message":"request is too large"},"id":null}`,
+		serve(`{"jsonrpc":"2.0","method":"echo","params":{"a":"`+strings.Repeat("a", 100)+`","b":1},"id":1}`))
+
+	// Requests that were read before reaching the limit are executed.
+	assert.Equal(t, `[{"jsonrpc":"2.0","result":{"b":1,"a":"abc"},"id":1},`+
+		`{"jsonrpc":"2.0","error":{"code":-32600,"message":"request is too large"},"id":null}]`,
+		serve(`[{"jsonrpc":"2.0","method":"echo","params":{"a":"abc","b":1},"id":1},`+
+			`{"jsonrpc":"2.0","method":"echo","params":{"a":"abc","b":2},"id":2}]`))
+
+	// Invalid requests of a batch do not stop decoding.
+	assert.Equal(t, `[{"jsonrpc":"2.0","error":{"code":-32600,"message":"failed to unmarshal request: `+
+		`json: cannot unmarshal number into Go value of type jsonrpc.Request"},"id":null},`+
+		`{"jsonrpc":"2.0","result":{"b":1,"a":"abc"},"id":1}]`,
+		serve(`[1,{"jsonrpc":"2.0","method":"echo","params":{"a":"abc","b":1},"id":1}]`))
+}
+
+func TestHandler_ServeHTTP_headers(t *testing.T) {
+	type inp struct {
+		Name    string `json:"name"`
+		Auth    string `header:"Authorization"`
+		Retries int    `header:"X-Retries"`
+		Session string `cookie:"session"`
+	}
+
+	u := usecase.NewIOI(new(inp), new(inp), func(ctx context.Context, input, output interface{}) error {
+		*output.(*inp) = *input.(*inp)
+
+		return nil
+	})
+	u.SetName("headers")
+
+	h := jsonrpc.Handler{}
+	h.OpenAPI = &jsonrpc.OpenAPI{}
+	h.Validator = &jsonrpc.JSONSchemaValidator{}
+	h.Add(u)
+
+	req, err := http.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(
+		`{"jsonrpc":"2.0","method":"headers","params":["foo"],"id":1}`)))
+	require.NoError(t, err)
+
+	req.Header.Set("Authorization", "Bearer abc")
+	req.Header.Set("X-Retries", "3")
+	req.AddCookie(&http.Cookie{Name: "session", Value: "def"})
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	assert.Equal(t, `{"jsonrpc":"2.0","result":{"name":"foo","Auth":"Bearer abc","Retries":3,"Session":"def"},"id":1}`,
+		w.Body.String())
+
+	// Header fields can not be passed with params.
+	req, err = http.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(
+		`{"jsonrpc":"2.0","method":"headers","params":{"name":"foo","Auth":"Bearer xyz"},"id":1}`)))
+	require.NoError(t, err)
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	assert.Equal(t, `{"jsonrpc":"2.0","result":{"name":"foo","Auth":"","Retries":0,"Session":""},"id":1}`,
+		w.Body.String())
+
+	req, err = http.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(
+		`{"jsonrpc":"2.0","method":"headers","params":{"name":"foo"},"id":1}`)))
+	require.NoError(t, err)
+
+	req.Header.Set("X-Retries", "many")
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	assert.Equal(t, `{"jsonrpc":"2.0","error":{"code":-32602,"message":"failed to unmarshal parameters",`+
+		`"data":"failed to decode X-Retries: invalid character 'm' looking for beginning of value"},"id":1}`,
+		w.Body.String())
+
+	op := h.OpenAPI.Reflector().SpecEns().Paths.MapOfPathItemValues["headers"].MapOfOperationValues["post"]
+
+	var params []string
+
+	for _, p := range op.Parameters {
+		params = append(params, string(p.Parameter.In)+":"+p.Parameter.Name)
+	}
+
+	assert.ElementsMatch(t, []string{"header:Authorization", "header:X-Retries", "cookie:session"}, params)
+	assert.Equal(t, []string{"name"}, op.MapOfAnything["x-params-by-position"])
+
+	// Field can not be both param and header.
+	type conflict struct {
+		Token string `json:"token" header:"Authorization"`
+	}
+
+	u = usecase.NewIOI(new(conflict), nil, func(ctx context.Context, input, output interface{}) error {
+		return nil
+	})
+	u.SetName("conflict")
+
+	assert.PanicsWithValue(t, `failed to setup header parameters: field Token with Authorization tag has json name "token"`,
+		func() { h.Add(u) })
+
+	_, found := h.OpenAPI.Reflector().SpecEns().Paths.MapOfPathItemValues["conflict"]
+	assert.False(t, found)
+}
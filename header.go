@@ -0,0 +1,119 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// headerField is an input field that is populated from HTTP request header or cookie.
+type headerField struct {
+	index  []int
+	name   string
+	cookie bool
+}
+
+// headerFields returns input fields with `header` or `cookie` tags.
+func headerFields(t reflect.Type) ([]headerField, error) {
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, nil
+	}
+
+	var res []headerField
+
+	for _, f := range reflect.VisibleFields(t) {
+		if f.Anonymous || !f.IsExported() {
+			continue
+		}
+
+		hf := headerField{index: f.Index}
+
+		if name := f.Tag.Get("header"); name != "" {
+			hf.name = name
+		} else if name := f.Tag.Get("cookie"); name != "" {
+			hf.name = name
+			hf.cookie = true
+		} else {
+			continue
+		}
+
+		// Field with JSON name would be documented as a param while its value is always taken from request.
+		if name := strings.Split(f.Tag.Get("json"), ",")[0]; name != "" && name != "-" {
+			return nil, fmt.Errorf("field %s with %s tag has json name %q", f.Name, hf.name, name)
+		}
+
+		// Embedded pointers would need allocation.
+		for i := 1; i < len(f.Index); i++ {
+			if t.FieldByIndex(f.Index[:i]).Type.Kind() == reflect.Ptr {
+				return nil, fmt.Errorf("field %s with %s tag is embedded by pointer", f.Name, hf.name)
+			}
+		}
+
+		res = append(res, hf)
+	}
+
+	return res, nil
+}
+
+// isHeaderField checks if struct field is populated from HTTP request header or cookie.
+func isHeaderField(f reflect.StructField) bool {
+	return f.Tag.Get("header") != "" || f.Tag.Get("cookie") != ""
+}
+
+// decodeHeaders populates input fields from originating HTTP request.
+//
+// Fields are reset if there is no HTTP request, so that they can not be passed with params.
+func decodeHeaders(ctx context.Context, fields []headerField, input interface{}) error {
+	r, _ := ctx.Value(httpRequestCtxKey{}).(*http.Request)
+	v := reflect.ValueOf(input).Elem()
+
+	for _, hf := range fields {
+		fv := v.FieldByIndex(hf.index)
+		fv.Set(reflect.Zero(fv.Type()))
+
+		if r == nil {
+			continue
+		}
+
+		var value string
+
+		if hf.cookie {
+			c, err := r.Cookie(hf.name)
+			if err != nil {
+				continue
+			}
+
+			value = c.Value
+		} else {
+			value = r.Header.Get(hf.name)
+		}
+
+		if value == "" {
+			continue
+		}
+
+		if err := setHeaderValue(fv, value); err != nil {
+			return fmt.Errorf("failed to decode %s: %w", hf.name, err)
+		}
+	}
+
+	return nil
+}
+
+func setHeaderValue(fv reflect.Value, value string) error {
+	if tu, ok := fv.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return tu.UnmarshalText([]byte(value))
+	}
+
+	if fv.Kind() == reflect.String {
+		fv.SetString(value)
+
+		return nil
+	}
+
+	return json.Unmarshal([]byte(value), fv.Addr().Interface())
+}
@@ -0,0 +1,29 @@
+package jsonrpc
+
+import "io"
+
+// limitedReader reads at most n bytes and fails with errRequestTooLarge if there is more data.
+type limitedReader struct {
+	r io.Reader
+	n int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.n < 0 {
+		return 0, errRequestTooLarge
+	}
+
+	// Reading one extra byte to detect excess.
+	if int64(len(p)) > l.n+1 {
+		p = p[:l.n+1]
+	}
+
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+
+	if l.n < 0 {
+		return n - 1, errRequestTooLarge
+	}
+
+	return n, err
+}
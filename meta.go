@@ -0,0 +1,63 @@
+package jsonrpc
+
+import (
+	"context"
+	"net/http"
+)
+
+// RequestMeta describes JSON-RPC request that is being served.
+type RequestMeta struct {
+	// Method is a name of called method, for alias it is a name of target method.
+	Method string
+
+	// Alias is a name of called alias, it is empty if method was called by its own name.
+	Alias string
+
+	// ID is a request id, it is nil for notification.
+	ID *interface{}
+
+	// Notification is true if request has no id and does not expect response.
+	Notification bool
+
+	// Batch is true if request is a part of batch.
+	Batch bool
+
+	// HTTPRequest is an originating HTTP request, for persistent connections it is a connection request,
+	// it is nil for stream connections.
+	HTTPRequest *http.Request
+}
+
+type (
+	metaCtxKey        struct{}
+	batchCtxKey       struct{}
+	httpRequestCtxKey struct{}
+)
+
+// MetaFromContext returns metadata of JSON-RPC request that is being served.
+func MetaFromContext(ctx context.Context) (RequestMeta, bool) {
+	m, ok := ctx.Value(metaCtxKey{}).(RequestMeta)
+
+	return m, ok
+}
+
+func withMeta(ctx context.Context, req Request) context.Context {
+	m := RequestMeta{
+		Method:       req.Method,
+		ID:           req.ID,
+		Notification: req.ID == nil,
+	}
+
+	m.Batch, _ = ctx.Value(batchCtxKey{}).(bool)
+	m.HTTPRequest, _ = ctx.Value(httpRequestCtxKey{}).(*http.Request)
+
+	return context.WithValue(ctx, metaCtxKey{}, m)
+}
+
+// withAlias updates request metadata for call of an alias.
+func withAlias(ctx context.Context, target string) context.Context {
+	m, _ := MetaFromContext(ctx)
+	m.Alias = m.Method
+	m.Method = target
+
+	return context.WithValue(ctx, metaCtxKey{}, m)
+}
@@ -0,0 +1,76 @@
+package jsonrpc_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/jsonrpc"
+	"github.com/swaggest/usecase"
+)
+
+func TestMetaFromContext(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		metas = map[string]jsonrpc.RequestMeta{}
+	)
+
+	u := usecase.NewIOI(new(string), nil, func(ctx context.Context, input, output interface{}) error {
+		m, ok := jsonrpc.MetaFromContext(ctx)
+		assert.True(t, ok)
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		metas[*input.(*string)] = m
+
+		return nil
+	})
+	u.SetName("meta")
+
+	h := jsonrpc.Handler{}
+	h.Add(u)
+
+	_, ok := jsonrpc.MetaFromContext(context.Background())
+	assert.False(t, ok)
+
+	req, err := http.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(
+		`{"jsonrpc":"2.0","method":"meta","params":"single","id":"abc"}`)))
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	m := metas["single"]
+	assert.Equal(t, "meta", m.Method)
+	require.NotNil(t, m.ID)
+	assert.Equal(t, "abc", *m.ID)
+	assert.False(t, m.Notification)
+	assert.False(t, m.Batch)
+	assert.Equal(t, req, m.HTTPRequest)
+
+	req, err = http.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(`[
+		{"jsonrpc":"2.0","method":"meta","params":"call","id":1},
+		{"jsonrpc":"2.0","method":"meta","params":"notification"}
+	]`)))
+	require.NoError(t, err)
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	m = metas["call"]
+	assert.True(t, m.Batch)
+	assert.False(t, m.Notification)
+	require.NotNil(t, m.ID)
+	assert.Equal(t, 1.0, *m.ID)
+
+	m = metas["notification"]
+	assert.True(t, m.Batch)
+	assert.True(t, m.Notification)
+	assert.Nil(t, m.ID)
+}
@@ -0,0 +1,149 @@
+package jsonrpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var errNotificationQueueFull = errors.New("notification queue is full")
+
+// notificationPool executes notifications in background workers.
+type notificationPool struct {
+	once sync.Once
+
+	mu     sync.RWMutex
+	closed bool
+	queue  chan asyncCall
+
+	wg sync.WaitGroup
+}
+
+type asyncCall struct {
+	ctx context.Context
+	req Request
+	end func()
+}
+
+// isAsyncNotification checks if request should be executed in background.
+func (h *Handler) isAsyncNotification(ctx context.Context, req Request) bool {
+	if req.ID != nil || h.NotificationWorkers <= 0 {
+		return false
+	}
+
+	// Only HTTP requests wait for execution, persistent connections do not.
+	if _, ok := PeerFromContext(ctx); ok {
+		return false
+	}
+
+	_, ok := ctx.Value(httpRequestCtxKey{}).(*http.Request)
+
+	return ok
+}
+
+// enqueueNotification schedules notification for background execution.
+func (h *Handler) enqueueNotification(ctx context.Context, req Request) (ErrorCode, error) {
+	np := &h.notifications
+
+	np.once.Do(func() {
+		size := h.NotificationQueueSize
+		if size <= 0 {
+			size = h.NotificationWorkers
+		}
+
+		np.queue = make(chan asyncCall, size)
+
+		for i := 0; i < h.NotificationWorkers; i++ {
+			np.wg.Add(1)
+
+			go func() {
+				defer np.wg.Done()
+
+				for c := range np.queue {
+					h.call(c.ctx, c.req, &Response{})
+					c.end()
+				}
+			}()
+		}
+	})
+
+	np.mu.RLock()
+	defer np.mu.RUnlock()
+
+	if np.closed {
+		return CodeShuttingDown, errShuttingDown
+	}
+
+	// Queued notification is an in-flight call for graceful shutdown.
+	actx, end, ok := h.lifecycle.begin(detachedContext{parent: ctx})
+	if !ok {
+		return CodeShuttingDown, errShuttingDown
+	}
+
+	select {
+	case np.queue <- asyncCall{ctx: actx, req: req, end: end}:
+		return 0, nil
+	default:
+		end()
+
+		return CodeResourceExhausted, errNotificationQueueFull
+	}
+}
+
+// DrainNotifications stops accepting asynchronous notifications and waits until queued notifications are executed.
+//
+// Notifications received after draining are rejected with CodeShuttingDown.
+// Context error is returned if context is done before queue is drained.
+func (h *Handler) DrainNotifications(ctx context.Context) error {
+	np := &h.notifications
+
+	// Preventing start of workers after draining.
+	np.once.Do(func() {})
+
+	np.mu.Lock()
+	if !np.closed {
+		np.closed = true
+
+		if np.queue != nil {
+			close(np.queue)
+		}
+	}
+	np.mu.Unlock()
+
+	done := make(chan struct{})
+
+	go func() {
+		np.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// detachedContext keeps values of parent context, but is not cancelled with it.
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+func (d detachedContext) Value(key interface{}) interface{} {
+	return d.parent.Value(key)
+}
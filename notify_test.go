@@ -0,0 +1,97 @@
+package jsonrpc_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/jsonrpc"
+	"github.com/swaggest/usecase"
+)
+
+func TestHandler_NotificationWorkers(t *testing.T) {
+	var (
+		release = make(chan struct{})
+		done    int64
+	)
+
+	u := usecase.NewIOI(nil, nil, func(ctx context.Context, input, output interface{}) error {
+		<-release
+
+		// Context of HTTP request is done, but notification context is not.
+		assert.NoError(t, ctx.Err())
+
+		m, ok := jsonrpc.MetaFromContext(ctx)
+		assert.True(t, ok)
+		assert.True(t, m.Notification)
+
+		atomic.AddInt64(&done, 1)
+
+		return nil
+	})
+	u.SetName("work")
+
+	h := &jsonrpc.Handler{}
+	h.NotificationWorkers = 1
+	h.NotificationQueueSize = 1
+	h.Add(u)
+
+	notify := func() *httptest.ResponseRecorder {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, "/", bytes.NewReader([]byte(`{"jsonrpc":"2.0","method":"work"}`)))
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		return w
+	}
+
+	// First notification is taken by worker, second one waits in queue.
+	w := notify()
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, w.Body.String())
+
+	assert.Eventually(t, func() bool {
+		return notify().Code == http.StatusNoContent
+	}, time.Second, time.Millisecond)
+
+	w = notify()
+	assert.Equal(t, `{"jsonrpc":"2.0","error":{"code":-32008,"message":"notification queue is full"},"id":null}`, w.Body.String())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, h.DrainNotifications(ctx), context.DeadlineExceeded)
+
+	close(release)
+
+	require.NoError(t, h.DrainNotifications(context.Background()))
+	assert.Equal(t, int64(2), atomic.LoadInt64(&done))
+
+	w = notify()
+	assert.Equal(t, `{"jsonrpc":"2.0","error":{"code":-32050,"message":"server is shutting down"},"id":null}`, w.Body.String())
+}
+
+func TestHandler_ServeHTTP_notification(t *testing.T) {
+	h := &jsonrpc.Handler{}
+	h.Add(echoUseCase())
+
+	req, err := http.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(
+		`{"jsonrpc":"2.0","method":"echo","params":{"a":"abc","b":1}}`)))
+	require.NoError(t, err)
+
+	// Synchronous notification is responded with empty body after execution.
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Body.String())
+}
@@ -0,0 +1,430 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/swaggest/jsonschema-go"
+	"github.com/swaggest/usecase"
+)
+
+const (
+	openRPCVersion    = "1.2.6"
+	componentsSchemas = "#/components/schemas/"
+)
+
+// OpenRPCSpec is an OpenRPC 1.x service description document.
+type OpenRPCSpec struct {
+	OpenRPC    string             `json:"openrpc"`
+	Info       OpenRPCInfo        `json:"info"`
+	Servers    []OpenRPCServer    `json:"servers,omitempty"`
+	Methods    []OpenRPCMethod    `json:"methods"`
+	Components *OpenRPCComponents `json:"components,omitempty"`
+}
+
+// OpenRPCInfo describes service.
+type OpenRPCInfo struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// OpenRPCServer describes service location.
+type OpenRPCServer struct {
+	Name        string `json:"name,omitempty"`
+	URL         string `json:"url"`
+	Summary     string `json:"summary,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// OpenRPCMethod describes JSON-RPC method.
+type OpenRPCMethod struct {
+	Name           string                     `json:"name"`
+	Summary        string                     `json:"summary,omitempty"`
+	Description    string                     `json:"description,omitempty"`
+	Tags           []OpenRPCTag               `json:"tags,omitempty"`
+	ParamStructure string                     `json:"paramStructure,omitempty"`
+	Params         []OpenRPCContentDescriptor `json:"params"`
+	Result         *OpenRPCContentDescriptor  `json:"result,omitempty"`
+	Errors         []OpenRPCError             `json:"errors,omitempty"`
+	Deprecated     bool                       `json:"deprecated,omitempty"`
+}
+
+// OpenRPCTag describes method tag.
+type OpenRPCTag struct {
+	Name string `json:"name"`
+}
+
+// OpenRPCContentDescriptor describes method parameter or result.
+type OpenRPCContentDescriptor struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Required    bool              `json:"required,omitempty"`
+	Schema      jsonschema.Schema `json:"schema"`
+	Deprecated  bool              `json:"deprecated,omitempty"`
+}
+
+// OpenRPCError describes application error.
+type OpenRPCError struct {
+	Code    ErrorCode   `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+
+	// DataSchema is a specification extension that describes structure of error data.
+	DataSchema *jsonschema.Schema `json:"x-data-schema,omitempty"`
+}
+
+// OpenRPCComponents holds reusable schemas.
+type OpenRPCComponents struct {
+	Schemas map[string]jsonschema.Schema `json:"schemas,omitempty"`
+}
+
+// OpenRPC extracts OpenRPC documentation from use case interactors.
+type OpenRPC struct {
+	mu sync.Mutex
+
+	spec        *OpenRPCSpec
+	reflector   *jsonschema.Reflector
+	annotations map[string][]func(*OpenRPCMethod) error
+}
+
+// Reflector is an accessor to JSON Schema Reflector instance.
+func (c *OpenRPC) Reflector() *jsonschema.Reflector {
+	if c.reflector == nil {
+		c.reflector = &jsonschema.Reflector{}
+	}
+
+	return c.reflector
+}
+
+// SpecEns ensures returned OpenRPC document is not nil.
+func (c *OpenRPC) SpecEns() *OpenRPCSpec {
+	if c.spec == nil {
+		c.spec = &OpenRPCSpec{OpenRPC: openRPCVersion}
+	}
+
+	return c.spec
+}
+
+// Annotate adds OpenRPC method configuration that is applied during collection.
+func (c *OpenRPC) Annotate(name string, setup ...func(m *OpenRPCMethod) error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.annotations == nil {
+		c.annotations = make(map[string][]func(m *OpenRPCMethod) error)
+	}
+
+	c.annotations[name] = append(c.annotations[name], setup...)
+}
+
+// Collect adds use case handler to documentation.
+func (c *OpenRPC) Collect(
+	name string,
+	u usecase.Interactor,
+	v Validator,
+	annotations ...func(*OpenRPCMethod) error,
+) (err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("failed to reflect OpenRPC schema for %s: %w", name, err)
+		}
+	}()
+
+	m := OpenRPCMethod{
+		Name:   name,
+		Params: []OpenRPCContentDescriptor{},
+	}
+
+	if err = c.setupParams(&m, u, name, v); err != nil {
+		return fmt.Errorf("failed to setup params: %w", err)
+	}
+
+	if err = c.setupResult(&m, u, name, v); err != nil {
+		return fmt.Errorf("failed to setup result: %w", err)
+	}
+
+	c.processUseCase(&m, u)
+
+	errs, err := methodErrors(u, v)
+	if err != nil {
+		return fmt.Errorf("failed to setup errors: %w", err)
+	}
+
+	for _, e := range errs {
+		m.Errors = append(m.Errors, OpenRPCError{
+			Code:       e.Code,
+			Message:    e.Message,
+			DataSchema: e.DataSchema,
+		})
+	}
+
+	for _, setup := range c.annotations[name] {
+		if err = setup(&m); err != nil {
+			return err
+		}
+	}
+
+	for _, setup := range annotations {
+		if err = setup(&m); err != nil {
+			return err
+		}
+	}
+
+	spec := c.SpecEns()
+
+	for i, em := range spec.Methods {
+		if em.Name == name {
+			spec.Methods[i] = m
+
+			return nil
+		}
+	}
+
+	spec.Methods = append(spec.Methods, m)
+
+	return nil
+}
+
+func (c *OpenRPC) collectDefinition(name string, schema jsonschema.Schema) {
+	spec := c.SpecEns()
+
+	if spec.Components == nil {
+		spec.Components = &OpenRPCComponents{}
+	}
+
+	if spec.Components.Schemas == nil {
+		spec.Components.Schemas = make(map[string]jsonschema.Schema)
+	}
+
+	if _, exists := spec.Components.Schemas[name]; exists {
+		return
+	}
+
+	spec.Components.Schemas[name] = schema
+}
+
+func (c *OpenRPC) reflect(i interface{}, options ...func(rc *jsonschema.ReflectContext)) (jsonschema.Schema, error) {
+	options = append([]func(rc *jsonschema.ReflectContext){
+		jsonschema.DefinitionsPrefix(componentsSchemas),
+		jsonschema.CollectDefinitions(c.collectDefinition),
+	}, options...)
+
+	return c.Reflector().Reflect(i, options...)
+}
+
+func (c *OpenRPC) setupParams(m *OpenRPCMethod, u usecase.Interactor, method string, v Validator) error {
+	var hasInput usecase.HasInputPort
+
+	if !usecase.As(u, &hasInput) || hasInput.InputPort() == nil {
+		return nil
+	}
+
+	input := hasInput.InputPort()
+
+	schema, err := c.reflect(input, jsonschema.RootRef)
+	if err != nil {
+		return err
+	}
+
+	if schema.Ref != nil {
+		schema = c.SpecEns().Components.Schemas[strings.TrimPrefix(*schema.Ref, componentsSchemas)]
+	}
+
+	positional, err := inputPositionalParams(input)
+	if err != nil {
+		return err
+	}
+
+	required := make(map[string]bool, len(schema.Required))
+	for _, r := range schema.Required {
+		required[r] = true
+	}
+
+	// Params are listed in positional order, params that are only available by name go last.
+	names := append([]string{}, positional...)
+	known := make(map[string]bool, len(names))
+
+	for _, n := range names {
+		known[n] = true
+	}
+
+	for _, n := range paramNames(reflect.TypeOf(input)) {
+		if !known[n] {
+			known[n] = true
+			names = append(names, n)
+		}
+	}
+
+	// Properties that are not struct fields (e.g. exposed by custom schema) go last in alphabetical order.
+	var extra []string
+
+	for n := range schema.Properties {
+		if !known[n] {
+			extra = append(extra, n)
+		}
+	}
+
+	sort.Strings(extra)
+
+	names = append(names, extra...)
+
+	// Positional structure is only advertised if every param can be passed by position.
+	m.ParamStructure = "by-name"
+
+	if len(positional) > 0 && len(positional) == len(names) {
+		m.ParamStructure = "either"
+	}
+
+	for _, n := range names {
+		ps, found := schema.Properties[n]
+		if !found {
+			continue
+		}
+
+		cd := OpenRPCContentDescriptor{
+			Name:     n,
+			Required: required[n],
+		}
+
+		if ps.TypeObject != nil {
+			cd.Schema = *ps.TypeObject
+
+			if ps.TypeObject.Description != nil {
+				cd.Description = *ps.TypeObject.Description
+			}
+		}
+
+		m.Params = append(m.Params, cd)
+	}
+
+	if v != nil {
+		return c.provideSchema(method, input, v.AddParamsSchema)
+	}
+
+	return nil
+}
+
+func (c *OpenRPC) setupResult(m *OpenRPCMethod, u usecase.Interactor, method string, v Validator) error {
+	var hasOutput usecase.HasOutputPort
+
+	if !usecase.As(u, &hasOutput) || hasOutput.OutputPort() == nil {
+		return nil
+	}
+
+	output := hasOutput.OutputPort()
+
+	schema, err := c.reflect(output, jsonschema.RootRef)
+	if err != nil {
+		return err
+	}
+
+	m.Result = &OpenRPCContentDescriptor{
+		Name:   "result",
+		Schema: schema,
+	}
+
+	if v != nil {
+		return c.provideSchema(method, output, v.AddResultSchema)
+	}
+
+	return nil
+}
+
+func (c *OpenRPC) provideSchema(method string, value interface{}, add func(method string, jsonSchema []byte) error) error {
+	schema, err := c.Reflector().Reflect(value, jsonschema.InlineRefs)
+	if err != nil {
+		return err
+	}
+
+	if schema.IsTrivial() {
+		return nil
+	}
+
+	schemaData, err := schema.JSONSchemaBytes()
+	if err != nil {
+		return errors.New("failed to build JSON Schema")
+	}
+
+	if err := add(method, schemaData); err != nil {
+		return fmt.Errorf("failed to add validation schema: %w", err)
+	}
+
+	return nil
+}
+
+func (c *OpenRPC) processUseCase(m *OpenRPCMethod, u usecase.Interactor) {
+	var (
+		hasTitle       usecase.HasTitle
+		hasDescription usecase.HasDescription
+		hasTags        usecase.HasTags
+		hasDeprecated  usecase.HasIsDeprecated
+	)
+
+	if usecase.As(u, &hasTitle) {
+		m.Summary = hasTitle.Title()
+	}
+
+	if usecase.As(u, &hasTags) {
+		for _, t := range hasTags.Tags() {
+			m.Tags = append(m.Tags, OpenRPCTag{Name: t})
+		}
+	}
+
+	if usecase.As(u, &hasDescription) {
+		m.Description = hasDescription.Description()
+	}
+
+	if usecase.As(u, &hasDeprecated) && hasDeprecated.IsDeprecated() {
+		m.Deprecated = true
+	}
+}
+
+// remove removes method from OpenRPC document.
+func (c *OpenRPC) remove(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	spec := c.SpecEns()
+
+	for i, m := range spec.Methods {
+		if m.Name == name {
+			spec.Methods = append(spec.Methods[:i], spec.Methods[i+1:]...)
+
+			return
+		}
+	}
+}
+
+func (c *OpenRPC) ServeHTTP(rw http.ResponseWriter, _ *http.Request) {
+	document, err := c.document()
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json; charset=utf8")
+
+	_, err = rw.Write(document)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// document returns JSON encoded OpenRPC document.
+func (c *OpenRPC) document() ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return json.MarshalIndent(c.SpecEns(), "", " ")
+}
@@ -0,0 +1,160 @@
+package jsonrpc_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/jsonrpc"
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+)
+
+func TestOpenRPC_Collect(t *testing.T) {
+	doc := jsonrpc.OpenRPC{}
+	doc.SpecEns().Info.Title = "JSON-RPC Example"
+	doc.SpecEns().Info.Version = "v1.2.3"
+
+	h := &jsonrpc.Handler{}
+	h.OpenRPC = &doc
+	h.Validator = &jsonrpc.JSONSchemaValidator{}
+
+	type inp struct {
+		Name  string `json:"name" required:"true" description:"Name to measure."`
+		Extra *inp   `json:"extra,omitempty"`
+	}
+
+	type out struct {
+		Len int `json:"len"`
+	}
+
+	u := usecase.NewIOI(new(inp), new(out), func(ctx context.Context, input, output interface{}) error {
+		output.(*out).Len = len(input.(*inp).Name)
+
+		return nil
+	})
+	u.SetTitle("Test")
+	u.SetDescription("Test Description")
+	u.SetName("nameLength")
+	u.SetTags("strings")
+	u.SetIsDeprecated(true)
+	u.SetExpectedErrors(status.NotFound, &jsonrpc.Error{Code: 1001, Message: "too long"})
+
+	h.Add(u)
+
+	assertjson.EqualMarshal(t, []byte(`{
+	  "openrpc":"1.2.6",
+	  "info":{"title":"JSON-RPC Example","version":"v1.2.3"},
+	  "methods":[
+		{
+		  "name":"nameLength","summary":"Test","description":"Test Description",
+		  "tags":[{"name":"strings"}],"paramStructure":"either",
+		  "params":[
+			{
+			  "name":"name","description":"Name to measure.","required":true,
+			  "schema":{"description":"Name to measure.","type":"string"}
+			},
+			{"name":"extra","schema":{"$ref":"#/components/schemas/JsonrpcTestInp"}}
+		  ],
+		  "result":{"name":"result","schema":{"$ref":"#/components/schemas/JsonrpcTestOut"}},
+		  "errors":[
+			{
+			  "code":-32602,"message":"invalid parameters",
+			  "x-data-schema":{
+				"properties":{
+				  "context":{
+					"additionalProperties":{"items":{"type":"string"},"type":"array"},
+					"type":["object","null"]
+				  },
+				  "error":{"type":"string"}
+				},
+				"type":"object"
+			  }
+			},
+			{"code":-32005,"message":"not found","x-data-schema":{"type":"string"}},
+			{"code":1001,"message":"too long"}
+		  ],
+		  "deprecated":true
+		}
+	  ],
+	  "components":{
+		"schemas":{
+		  "JsonrpcTestInp":{
+			"required":["name"],
+			"properties":{
+			  "extra":{"$ref":"#/components/schemas/JsonrpcTestInp"},
+			  "name":{"description":"Name to measure.","type":"string"}
+			},
+			"type":"object"
+		  },
+		  "JsonrpcTestOut":{"properties":{"len":{"type":"integer"}},"type":"object"}
+		}
+	  }
+	}`), doc.SpecEns())
+
+	rw := httptest.NewRecorder()
+	doc.ServeHTTP(rw, nil)
+	assert.Equal(t, http.StatusOK, rw.Code)
+
+	rw = httptest.NewRecorder()
+	h.ServeHTTP(rw, httptest.NewRequest(http.MethodPost, "/",
+		strings.NewReader(`{"jsonrpc":"2.0","method":"nameLength","params":{},"id":1}`)))
+	assert.Equal(t, `{"jsonrpc":"2.0","error":{"code":-32602,"message":"invalid parameters","data":{"error":"validation failed","context":{"params":["#: missing properties: \"name\""]}}},"id":1}`, rw.Body.String())
+}
+
+func TestOpenRPC_Collect_positional(t *testing.T) {
+	doc := jsonrpc.OpenRPC{}
+	h := &jsonrpc.Handler{}
+	h.OpenRPC = &doc
+
+	type ordered struct {
+		Name   string `json:"name" rpcpos:"1"`
+		Repeat int    `json:"repeat" rpcpos:"0"`
+	}
+
+	type mixed struct {
+		Name   string `json:"name" rpcpos:"1"`
+		Repeat int    `json:"repeat" rpcpos:"0"`
+		Skip   bool   `json:"skip"`
+	}
+
+	u := usecase.NewIOI(new(ordered), nil, func(ctx context.Context, input, output interface{}) error {
+		return nil
+	})
+	u.SetName("ordered")
+	h.Add(u)
+
+	u = usecase.NewIOI(new(mixed), nil, func(ctx context.Context, input, output interface{}) error {
+		return nil
+	})
+	u.SetName("mixed")
+	h.Add(u)
+
+	methods := doc.SpecEns().Methods
+	for i := range methods {
+		methods[i].Summary = ""
+		methods[i].Errors = nil
+	}
+
+	assertjson.EqualMarshal(t, []byte(`[
+	  {
+		"name":"ordered","paramStructure":"either",
+		"params":[
+		  {"name":"repeat","schema":{"type":"integer"}},
+		  {"name":"name","schema":{"type":"string"}}
+		]
+	  },
+	  {
+		"name":"mixed","paramStructure":"by-name",
+		"params":[
+		  {"name":"repeat","schema":{"type":"integer"}},
+		  {"name":"name","schema":{"type":"string"}},
+		  {"name":"skip","schema":{"type":"boolean"}}
+		]
+	  }
+	]`), methods)
+}
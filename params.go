@@ -0,0 +1,180 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// paramNames returns JSON names of struct fields in order of declaration.
+//
+// Fields of embedded structures are inlined at the position of embedding.
+func paramNames(t reflect.Type) []string {
+	fields := paramFields(t)
+	names := make([]string, 0, len(fields))
+
+	for _, f := range fields {
+		names = append(names, f.name)
+	}
+
+	return names
+}
+
+type paramField struct {
+	name string
+	pos  string
+}
+
+func paramFields(t reflect.Type) []paramField {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var fields []paramField
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		tag := f.Tag.Get("json")
+		name := strings.Split(tag, ",")[0]
+
+		if name == "-" {
+			continue
+		}
+
+		if f.Anonymous && name == "" {
+			fields = append(fields, paramFields(f.Type)...)
+
+			continue
+		}
+
+		if !f.IsExported() || isHeaderField(f) {
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		fields = append(fields, paramField{name: name, pos: f.Tag.Get("rpcpos")})
+	}
+
+	return fields
+}
+
+// positionalParams returns names of parameters by their position.
+//
+// Fields are positioned in order of declaration, unless there are fields with `rpcpos:"<index>"` tag,
+// in that case only tagged fields are available by position.
+func positionalParams(t reflect.Type) ([]string, error) {
+	fields := paramFields(t)
+	tagged := make(map[int]string)
+	maxPos := -1
+
+	for _, f := range fields {
+		if f.pos == "" {
+			continue
+		}
+
+		pos, err := strconv.Atoi(f.pos)
+		if err != nil || pos < 0 {
+			return nil, fmt.Errorf("invalid rpcpos value %q of %s", f.pos, f.name)
+		}
+
+		if n, ok := tagged[pos]; ok {
+			return nil, fmt.Errorf("duplicate rpcpos %d of %s and %s", pos, n, f.name)
+		}
+
+		tagged[pos] = f.name
+
+		if pos > maxPos {
+			maxPos = pos
+		}
+	}
+
+	if len(tagged) == 0 {
+		return paramNames(t), nil
+	}
+
+	names := make([]string, maxPos+1)
+
+	for pos := range names {
+		n, ok := tagged[pos]
+		if !ok {
+			return nil, fmt.Errorf("missing rpcpos %d", pos)
+		}
+
+		names[pos] = n
+	}
+
+	return names, nil
+}
+
+// inputPositionalParams returns names of parameters by position for an input value of struct type.
+func inputPositionalParams(input interface{}) ([]string, error) {
+	t := reflect.TypeOf(input)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, nil
+	}
+
+	return positionalParams(t)
+}
+
+// isPositional checks if params are passed by position as JSON array.
+func isPositional(params []byte) bool {
+	params = bytes.TrimLeft(params, " \t\r\n")
+
+	return len(params) > 0 && params[0] == '['
+}
+
+// namedParams converts positional params to JSON object with names of params as keys.
+//
+// Null values are omitted.
+func namedParams(c Codec, params []byte, names []string) ([]byte, error) {
+	var values []json.RawMessage
+
+	if err := c.Unmarshal(params, &values); err != nil {
+		return nil, err
+	}
+
+	if len(values) > len(names) {
+		return nil, fmt.Errorf("too many positional parameters: %d, expected at most %d", len(values), len(names))
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, len(params)+len(values)*16))
+	buf.WriteByte('{')
+
+	for i, v := range values {
+		if bytes.Equal(v, []byte("null")) {
+			continue
+		}
+
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+
+		k, err := c.Marshal(names[i])
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,188 @@
+package jsonrpc
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+
+	"github.com/swaggest/usecase"
+)
+
+// Peer is a client of a persistent connection, it allows sending notifications from server.
+//
+// Peer is available in context of calls received with persistent transport, see PeerFromContext.
+type Peer struct {
+	mc    messageConn
+	codec Codec
+
+	writeMu sync.Mutex
+
+	mu     sync.Mutex
+	closed bool
+	subSeq uint64
+	subs   map[string]*Subscription
+	calls  map[string]*inFlightCall
+}
+
+type peerCtxKey struct{}
+
+// PeerFromContext returns peer of persistent connection.
+func PeerFromContext(ctx context.Context) (*Peer, bool) {
+	p, ok := ctx.Value(peerCtxKey{}).(*Peer)
+
+	return p, ok
+}
+
+var errSubscriptionDone = errors.New("subscription is done")
+
+// Notify sends JSON-RPC notification to the peer.
+func (p *Peer) Notify(method string, params interface{}) error {
+	req := Request{
+		JSONRPC: ver,
+		Method:  method,
+	}
+
+	if params != nil {
+		data, err := p.codec.Marshal(params)
+		if err != nil {
+			return err
+		}
+
+		req.Params = data
+	}
+
+	data, err := p.codec.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	return p.write(data)
+}
+
+// Subscribe creates a subscription with unique id, subscription events are sent as notifications of method.
+//
+// Subscription is done when it is unsubscribed or connection is closed.
+// Events that are sent before subscribing call returns may arrive ahead of its response.
+func (p *Peer) Subscribe(method string) *Subscription {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.subSeq++
+
+	s := &Subscription{
+		ID:     "0x" + strconv.FormatUint(p.subSeq, 16),
+		method: method,
+		p:      p,
+		done:   make(chan struct{}),
+	}
+
+	if p.closed {
+		close(s.done)
+
+		return s
+	}
+
+	if p.subs == nil {
+		p.subs = make(map[string]*Subscription)
+	}
+
+	p.subs[s.ID] = s
+
+	return s
+}
+
+// Unsubscribe cancels subscription by id, it returns false if subscription is not found.
+func (p *Peer) Unsubscribe(id string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	s, ok := p.subs[id]
+	if !ok {
+		return false
+	}
+
+	delete(p.subs, id)
+	close(s.done)
+
+	return true
+}
+
+func (p *Peer) closeSubscriptions() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closed = true
+
+	for id, s := range p.subs {
+		delete(p.subs, id)
+		close(s.done)
+	}
+}
+
+func (p *Peer) write(data []byte) error {
+	p.writeMu.Lock()
+	defer p.writeMu.Unlock()
+
+	return p.mc.WriteMessage(data)
+}
+
+// Subscription streams events to the peer.
+type Subscription struct {
+	ID string
+
+	method string
+	p      *Peer
+	done   chan struct{}
+}
+
+// Done is closed when subscription is cancelled or connection is closed.
+func (s *Subscription) Done() <-chan struct{} {
+	return s.done
+}
+
+type subscriptionEvent struct {
+	Subscription string      `json:"subscription"`
+	Result       interface{} `json:"result"`
+}
+
+// Notify sends subscription event to the peer.
+//
+// Notification params have subscription id and result, e.g. {"subscription":"0x1","result":...}.
+func (s *Subscription) Notify(result interface{}) error {
+	select {
+	case <-s.done:
+		return errSubscriptionDone
+	default:
+	}
+
+	return s.p.Notify(s.method, subscriptionEvent{Subscription: s.ID, Result: result})
+}
+
+// UnsubscribeUseCase creates use case to cancel subscription of the peer.
+//
+// Subscription id is passed in "subscription" parameter, by name or by position.
+func UnsubscribeUseCase(name string) usecase.Interactor {
+	type unsubscribeInput struct {
+		Subscription string `json:"subscription" required:"true"`
+	}
+
+	u := usecase.NewInteractor(func(ctx context.Context, input unsubscribeInput, output *bool) error {
+		p, ok := PeerFromContext(ctx)
+		if !ok {
+			return errNoPeer
+		}
+
+		*output = p.Unsubscribe(input.Subscription)
+
+		return nil
+	})
+
+	u.SetName(name)
+	u.SetTitle("Unsubscribe")
+	u.SetDescription("Cancels subscription by id.")
+
+	return u
+}
+
+var errNoPeer = errors.New("persistent connection is required")
@@ -0,0 +1,253 @@
+package jsonrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/swaggest/usecase/status"
+)
+
+// RateLimit configures token bucket rate limiting.
+type RateLimit struct {
+	// Rate is a number of calls per second.
+	Rate float64
+
+	// Burst is a maximum number of calls at once, default is Rate rounded up.
+	Burst int
+}
+
+func (rl RateLimit) burst() float64 {
+	if rl.Burst > 0 {
+		return float64(rl.Burst)
+	}
+
+	return math.Max(1, math.Ceil(rl.Rate))
+}
+
+// RateLimitError is returned for a call that exceeded rate limit.
+type RateLimitError struct {
+	RetryAfter time.Duration
+}
+
+// Error returns error message.
+func (e RateLimitError) Error() string {
+	return fmt.Sprintf("rate limit exceeded, retry after %ds", e.retryAfterSeconds())
+}
+
+// Status returns canonical status.
+func (e RateLimitError) Status() status.Code {
+	return status.ResourceExhausted
+}
+
+// Fields returns structured error context.
+func (e RateLimitError) Fields() map[string]interface{} {
+	return map[string]interface{}{
+		"retryAfter": e.retryAfterSeconds(),
+	}
+}
+
+func (e RateLimitError) retryAfterSeconds() int {
+	return int(math.Ceil(e.RetryAfter.Seconds()))
+}
+
+// RateLimitByIP returns client IP address as a rate limiting key.
+func RateLimitByIP(ctx context.Context) string {
+	m, ok := MetaFromContext(ctx)
+	if !ok || m.HTTPRequest == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(m.HTTPRequest.RemoteAddr)
+	if err != nil {
+		return m.HTTPRequest.RemoteAddr
+	}
+
+	return host
+}
+
+// RateLimitByPrincipal returns name of authenticated principal as a rate limiting key,
+// with fallback to client IP address.
+func RateLimitByPrincipal(ctx context.Context) string {
+	if p, ok := PrincipalFromContext(ctx); ok {
+		return "principal:" + p.Name
+	}
+
+	return RateLimitByIP(ctx)
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	rate   float64
+	burst  float64
+}
+
+// refill adds tokens accumulated since last update.
+func (b *bucket) refill(now time.Time) {
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+}
+
+// limitKey is a rate limit applied to a bucket.
+type limitKey struct {
+	key string
+	rl  RateLimit
+}
+
+// rateLimiter maintains token buckets by key.
+type rateLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	sweepAt int
+}
+
+// maxIdleBuckets is a minimal number of buckets that triggers removal of full buckets.
+const maxIdleBuckets = 10000
+
+// take consumes a token from every bucket and returns zero, or returns time to wait for tokens.
+//
+// Tokens are only consumed if all buckets have them.
+func (l *rateLimiter) take(now time.Time, limits ...limitKey) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.buckets == nil {
+		l.buckets = make(map[string]*bucket)
+	}
+
+	if len(l.buckets) >= l.sweepAt {
+		l.sweep(now)
+	}
+
+	var (
+		wait    time.Duration
+		buckets = make([]*bucket, 0, len(limits))
+	)
+
+	for _, lk := range limits {
+		b := l.bucket(lk, now)
+		buckets = append(buckets, b)
+
+		if b.tokens < 1 {
+			if w := time.Duration((1 - b.tokens) / b.rate * float64(time.Second)); w > wait {
+				wait = w
+			}
+		}
+	}
+
+	if wait > 0 {
+		return wait
+	}
+
+	for _, b := range buckets {
+		b.tokens--
+	}
+
+	return 0
+}
+
+// bucket returns refilled bucket of a key, new bucket is full.
+func (l *rateLimiter) bucket(lk limitKey, now time.Time) *bucket {
+	b, ok := l.buckets[lk.key]
+	if !ok {
+		b = &bucket{tokens: lk.rl.burst(), last: now}
+		l.buckets[lk.key] = b
+	}
+
+	b.rate = lk.rl.Rate
+	b.burst = lk.rl.burst()
+	b.refill(now)
+
+	return b
+}
+
+// sweep removes buckets that are refilled.
+//
+// Next sweep is postponed until number of buckets doubles, so that buckets that are not full do not cause
+// a sweep on every insert.
+func (l *rateLimiter) sweep(now time.Time) {
+	for key, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*b.rate >= b.burst {
+			delete(l.buckets, key)
+		}
+	}
+
+	l.sweepAt = 2 * len(l.buckets)
+	if l.sweepAt < maxIdleBuckets {
+		l.sweepAt = maxIdleBuckets
+	}
+}
+
+// rateLimit checks rate limits of client and method.
+func (h *Handler) rateLimit(ctx context.Context, method string) error {
+	var (
+		client string
+		limits []limitKey
+	)
+
+	if h.RateLimitKey != nil {
+		client = h.RateLimitKey(ctx)
+	}
+
+	if h.ClientRateLimit.Rate > 0 && client != "" {
+		limits = append(limits, limitKey{key: "client\x00" + client, rl: h.ClientRateLimit})
+	}
+
+	if rl, ok := h.RateLimits[method]; ok && rl.Rate > 0 {
+		limits = append(limits, limitKey{key: "method\x00" + method + "\x00" + client, rl: rl})
+	}
+
+	if len(limits) == 0 {
+		return nil
+	}
+
+	if wait := h.limiter.take(time.Now(), limits...); wait > 0 {
+		return RateLimitError{RetryAfter: wait}
+	}
+
+	return nil
+}
+
+type httpLimitCtxKey struct{}
+
+// httpLimit tracks rate limited calls of HTTP request.
+type httpLimit struct {
+	mu         sync.Mutex
+	calls      int
+	limited    int
+	retryAfter time.Duration
+}
+
+func (hl *httpLimit) add(err error) {
+	hl.mu.Lock()
+	defer hl.mu.Unlock()
+
+	hl.calls++
+
+	var rle RateLimitError
+
+	if errors.As(err, &rle) {
+		hl.limited++
+
+		if rle.RetryAfter > hl.retryAfter {
+			hl.retryAfter = rle.RetryAfter
+		}
+	}
+}
+
+// setHeader sets Retry-After header if all calls of request were rate limited.
+func (hl *httpLimit) setHeader(w http.ResponseWriter) {
+	hl.mu.Lock()
+	defer hl.mu.Unlock()
+
+	if hl.calls > 0 && hl.calls == hl.limited {
+		w.Header().Set("Retry-After", strconv.Itoa(RateLimitError{RetryAfter: hl.retryAfter}.retryAfterSeconds()))
+	}
+}
@@ -0,0 +1,77 @@
+package jsonrpc_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/jsonrpc"
+)
+
+func TestHandler_RateLimits(t *testing.T) {
+	h := jsonrpc.Handler{}
+	h.RateLimits = map[string]jsonrpc.RateLimit{
+		"echo": {Rate: 0.1, Burst: 2},
+	}
+	h.RateLimitKey = jsonrpc.RateLimitByIP
+	h.Add(echoUseCase())
+
+	call := func(remoteAddr, body string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(body)))
+		require.NoError(t, err)
+
+		req.RemoteAddr = remoteAddr
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		return w
+	}
+
+	single := `{"jsonrpc":"2.0","method":"echo","params":{"a":"abc","b":1},"id":1}`
+	limited := `{"jsonrpc":"2.0","error":{"code":-32008,"message":"resource exhausted",` +
+		`"data":{"error":"rate limit exceeded, retry after 10s","context":{"retryAfter":10}}},"id":1}`
+
+	w := call("10.0.0.1:1234", `[
+		{"jsonrpc":"2.0","method":"echo","params":{"a":"abc","b":1},"id":1},
+		{"jsonrpc":"2.0","method":"echo","params":{"a":"abc","b":1},"id":2}
+	]`)
+	assert.Equal(t, `[{"jsonrpc":"2.0","result":{"b":1,"a":"abc"},"id":1},{"jsonrpc":"2.0","result":{"b":1,"a":"abc"},"id":2}]`,
+		w.Body.String())
+	assert.Empty(t, w.Header().Get("Retry-After"))
+
+	w = call("10.0.0.1:1235", single)
+	assert.Contains(t, w.Body.String(), `"code":-32008`)
+	assert.Contains(t, w.Body.String(), `"retryAfter":10`)
+	assert.Equal(t, "10", w.Header().Get("Retry-After"))
+
+	// Other clients have separate limits.
+	w = call("10.0.0.2:1234", single)
+	assert.Equal(t, `{"jsonrpc":"2.0","result":{"b":1,"a":"abc"},"id":1}`, w.Body.String())
+
+	// Client limit applies to all methods.
+	h.ClientRateLimit = jsonrpc.RateLimit{Rate: 0.1, Burst: 1}
+
+	w = call("10.0.0.3:1234", `{"jsonrpc":"2.0","method":"unknown","id":1}`)
+	assert.Equal(t, `{"jsonrpc":"2.0","error":{"code":-32601,"message":"method not found: unknown"},"id":1}`, w.Body.String())
+
+	w = call("10.0.0.3:1234", single)
+	assert.Equal(t, limited, w.Body.String())
+	assert.Equal(t, "10", w.Header().Get("Retry-After"))
+
+	// Client token is not consumed by a call rejected with method limit.
+	h.ClientRateLimit = jsonrpc.RateLimit{Rate: 0.1, Burst: 2}
+	h.RateLimits["echo"] = jsonrpc.RateLimit{Rate: 0.1, Burst: 1}
+
+	w = call("10.0.0.4:1234", single)
+	assert.Equal(t, `{"jsonrpc":"2.0","result":{"b":1,"a":"abc"},"id":1}`, w.Body.String())
+
+	w = call("10.0.0.4:1234", single)
+	assert.Equal(t, limited, w.Body.String())
+
+	w = call("10.0.0.4:1234", `{"jsonrpc":"2.0","method":"unknown","id":1}`)
+	assert.Equal(t, `{"jsonrpc":"2.0","error":{"code":-32601,"message":"method not found: unknown"},"id":1}`, w.Body.String())
+}
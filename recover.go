@@ -0,0 +1,32 @@
+package jsonrpc
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"strings"
+)
+
+// recovered converts panic of method invocation into error response.
+func (h *Handler) recovered(ctx context.Context, req Request, resp *Response, rcv interface{}) {
+	stack := debug.Stack()
+
+	if h.OnPanic != nil {
+		h.OnPanic(ctx, req, rcv, stack)
+	}
+
+	resp.Result = nil
+	resp.Error = &Error{
+		Code:    CodeInternalError,
+		Message: "internal error",
+	}
+
+	if h.Debug {
+		resp.Error.Data = structuredErrorData{
+			Message: fmt.Sprintf("panic: %v", rcv),
+			Context: map[string]interface{}{
+				"stack": strings.Split(strings.TrimSpace(string(stack)), "\n"),
+			},
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package jsonrpc
+
+import (
+	"sort"
+
+	"github.com/swaggest/usecase"
+)
+
+// Remove unregisters method, it returns false if method is not found.
+//
+// Method is also removed from OpenAPI and OpenRPC documents and from Validator if it implements SchemaRemover.
+func (h *Handler) Remove(name string) bool {
+	h.registryMu.Lock()
+	defer h.registryMu.Unlock()
+
+	h.methodsMu.Lock()
+	_, found := h.methods[name]
+	delete(h.methods, name)
+	h.methodsMu.Unlock()
+
+	if !found {
+		return false
+	}
+
+	h.removeDocs(name)
+
+	return true
+}
+
+// removeDocs removes documentation and validation schemas of method.
+func (h *Handler) removeDocs(name string) {
+	if h.OpenAPI != nil {
+		h.OpenAPI.remove(name)
+	}
+
+	if h.OpenRPC != nil {
+		h.OpenRPC.remove(name)
+	}
+
+	if sr, ok := h.Validator.(SchemaRemover); ok {
+		sr.RemoveSchemas(name)
+	}
+}
+
+// Replace registers use case interactor instead of method with the same name.
+//
+// Documentation and validation schemas of previous method are replaced, method and its schemas
+// are swapped at once, so that concurrent calls find either previous or new method with matching schemas.
+//
+// Replace is equivalent to Add, it makes intent explicit.
+func (h *Handler) Replace(u usecase.Interactor, mw ...usecase.Middleware) {
+	h.Add(u, mw...)
+}
+
+// Methods returns sorted names of registered methods.
+func (h *Handler) Methods() []string {
+	h.methodsMu.RLock()
+	defer h.methodsMu.RUnlock()
+
+	names := make([]string, 0, len(h.methods))
+
+	for name := range h.methods {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,192 @@
+package jsonrpc_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/jsonrpc"
+	"github.com/swaggest/usecase"
+)
+
+func TestHandler_Remove(t *testing.T) {
+	h := &jsonrpc.Handler{}
+	h.OpenAPI = &jsonrpc.OpenAPI{}
+	h.OpenRPC = &jsonrpc.OpenRPC{}
+	h.Validator = &jsonrpc.JSONSchemaValidator{}
+
+	call := func(method string) string {
+		req, err := http.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(
+			`{"jsonrpc":"2.0","method":"`+method+`","params":{"a":"abc","b":1},"id":1}`)))
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		return w.Body.String()
+	}
+
+	wg := sync.WaitGroup{}
+
+	// Methods can be added while serving.
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			h.Add(echoUseCase())
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			call("echo")
+		}()
+	}
+
+	wg.Wait()
+
+	ping := usecase.NewIOI(nil, new(string), func(ctx context.Context, input, output interface{}) error {
+		*output.(*string) = "pong"
+
+		return nil
+	})
+	ping.SetName("ping")
+	h.Add(ping)
+
+	assert.Equal(t, []string{"echo", "ping"}, h.Methods())
+	assert.Equal(t, `{"jsonrpc":"2.0","result":{"b":1,"a":"abc"},"id":1}`, call("echo"))
+
+	assert.Error(t, h.Validator.ValidateParams("echo", []byte(`"abc"`)))
+	assert.True(t, h.Remove("echo"))
+	assert.False(t, h.Remove("echo"))
+
+	assert.Equal(t, []string{"ping"}, h.Methods())
+	assert.Equal(t, `{"jsonrpc":"2.0","error":{"code":-32601,"message":"method not found: echo"},"id":1}`, call("echo"))
+	assert.NotContains(t, h.OpenAPI.Reflector().SpecEns().Paths.MapOfPathItemValues, "echo")
+	require.Len(t, h.OpenRPC.SpecEns().Methods, 1)
+	assert.Equal(t, "ping", h.OpenRPC.SpecEns().Methods[0].Name)
+
+	assert.NoError(t, h.Validator.ValidateParams("echo", []byte(`"abc"`)))
+
+	pong := usecase.NewIOI(nil, new(string), func(ctx context.Context, input, output interface{}) error {
+		*output.(*string) = "PONG"
+
+		return nil
+	})
+	pong.SetName("ping")
+	pong.SetTitle("Loud ping")
+	h.Replace(pong)
+
+	assert.Equal(t, `{"jsonrpc":"2.0","result":"PONG","id":1}`, call("ping"))
+	require.Len(t, h.OpenRPC.SpecEns().Methods, 1)
+	assert.Equal(t, "Loud ping", h.OpenRPC.SpecEns().Methods[0].Summary)
+
+	// Schemas of previous method are replaced on Add with the same name.
+	type countInput struct {
+		N int `json:"n" minimum:"1"`
+	}
+
+	count := usecase.NewIOI(new(countInput), new(int), func(ctx context.Context, input, output interface{}) error {
+		*output.(*int) = input.(*countInput).N
+
+		return nil
+	})
+	count.SetName("ping")
+	h.Add(count)
+
+	assert.Error(t, h.Validator.ValidateParams("ping", []byte(`{"n":0}`)))
+
+	silent := usecase.NewIOI(nil, nil, func(ctx context.Context, input, output interface{}) error {
+		return nil
+	})
+	silent.SetName("ping")
+	h.Add(silent)
+
+	assert.NoError(t, h.Validator.ValidateParams("ping", []byte(`{"n":0}`)))
+	require.Len(t, h.OpenRPC.SpecEns().Methods, 1)
+	assert.Empty(t, h.OpenRPC.SpecEns().Methods[0].Params)
+}
+
+func TestHandler_Replace_concurrent(t *testing.T) {
+	h := &jsonrpc.Handler{}
+	h.OpenAPI = &jsonrpc.OpenAPI{}
+	h.OpenRPC = &jsonrpc.OpenRPC{}
+	h.Validator = &jsonrpc.JSONSchemaValidator{}
+
+	type shortInput struct {
+		S string `json:"s" maxLength:"3"`
+	}
+
+	type longInput struct {
+		S string `json:"s" minLength:"4"`
+	}
+
+	short := usecase.NewIOI(new(shortInput), nil, func(ctx context.Context, input, output interface{}) error {
+		return nil
+	})
+	short.SetName("check")
+	short.SetTitle("short")
+
+	long := usecase.NewIOI(new(longInput), nil, func(ctx context.Context, input, output interface{}) error {
+		return nil
+	})
+	long.SetName("check")
+	long.SetTitle("long")
+
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < 20; i++ {
+		wg.Add(3)
+
+		go func() {
+			defer wg.Done()
+
+			h.Replace(short)
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			h.Replace(long)
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			h.Remove("check")
+		}()
+	}
+
+	wg.Wait()
+
+	methods := h.OpenRPC.SpecEns().Methods
+	_, documented := h.OpenAPI.Reflector().SpecEns().Paths.MapOfPathItemValues["check"]
+
+	// Documentation and validation schemas belong to registered method.
+	if len(h.Methods()) == 0 {
+		assert.Empty(t, methods)
+		assert.False(t, documented)
+		assert.NoError(t, h.Validator.ValidateParams("check", []byte(`{"s":"ab"}`)))
+		assert.NoError(t, h.Validator.ValidateParams("check", []byte(`{"s":"abcde"}`)))
+
+		return
+	}
+
+	require.Len(t, methods, 1)
+	assert.True(t, documented)
+
+	if methods[0].Summary == "short" {
+		assert.NoError(t, h.Validator.ValidateParams("check", []byte(`{"s":"ab"}`)))
+		assert.Error(t, h.Validator.ValidateParams("check", []byte(`{"s":"abcde"}`)))
+	} else {
+		assert.Error(t, h.Validator.ValidateParams("check", []byte(`{"s":"ab"}`)))
+		assert.NoError(t, h.Validator.ValidateParams("check", []byte(`{"s":"abcde"}`)))
+	}
+}
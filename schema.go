@@ -6,12 +6,19 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/swaggest/openapi-go/openapi3"
 	"github.com/swaggest/usecase"
 )
 
+// xParamsByPosition is an OpenAPI operation extension that lists parameters names by position.
+const xParamsByPosition = "x-params-by-position"
+
+// xPermissions is an OpenAPI operation extension that lists permissions required to call method.
+const xPermissions = "x-required-permissions"
+
 // OpenAPI extracts OpenAPI documentation from HTTP handler and underlying use case interactor.
 type OpenAPI struct {
 	mu sync.Mutex
@@ -63,6 +70,9 @@ func (c *OpenAPI) Collect(
 	reflector.SpecEns().WithMapOfAnythingItem("x-envelope", "jsonrpc-2.0")
 
 	err = reflector.SpecEns().SetupOperation(http.MethodPost, name, func(op *openapi3.Operation) error {
+		// Operation of previously registered method is replaced, not amended.
+		*op = openapi3.Operation{}
+
 		oc := openapi3.OperationContext{
 			Operation:       op,
 			HTTPMethod:      http.MethodPost,
@@ -80,7 +90,16 @@ func (c *OpenAPI) Collect(
 			return fmt.Errorf("failed to setup response: %w", err)
 		}
 
-		c.processUseCase(op, u)
+		c.processUseCase(op, name, u)
+
+		errs, err := methodErrors(u, v)
+		if err != nil {
+			return fmt.Errorf("failed to setup errors: %w", err)
+		}
+
+		if len(errs) > 0 {
+			op.WithMapOfAnythingItem(xErrors, errs)
+		}
 
 		for _, setup := range c.annotations[name] {
 			err = setup(op)
@@ -143,26 +162,117 @@ func (c *OpenAPI) setupInput(oc *openapi3.OperationContext, u usecase.Interactor
 			return err
 		}
 
+		// Validation schema is provided before positional form is documented,
+		// positional params are validated after conversion to named params.
 		if v != nil {
-			return c.provideRequestJSONSchema(method, oc.Operation, v)
+			if err = c.provideRequestJSONSchema(method, oc.Operation, v); err != nil {
+				return err
+			}
+		}
+
+		return c.setupPositionalParams(oc)
+	}
+
+	return nil
+}
+
+// setupPositionalParams documents order of parameters that can be passed by position.
+func (c *OpenAPI) setupPositionalParams(oc *openapi3.OperationContext) error {
+	names, err := inputPositionalParams(oc.Input)
+	if err != nil {
+		return err
+	}
+
+	if len(names) == 0 {
+		return nil
+	}
+
+	oc.Operation.WithMapOfAnythingItem(xParamsByPosition, names)
+
+	if oc.Operation.RequestBody == nil || oc.Operation.RequestBody.RequestBody == nil {
+		return nil
+	}
+
+	for ct, content := range oc.Operation.RequestBody.RequestBody.Content {
+		if content.Schema == nil {
+			continue
+		}
+
+		content.Schema = &openapi3.SchemaOrRef{
+			Schema: (&openapi3.Schema{}).WithOneOf(*content.Schema, c.positionalSchema(*content.Schema, names)),
 		}
+		oc.Operation.RequestBody.RequestBody.Content[ct] = content
 	}
 
 	return nil
 }
 
-func (c *OpenAPI) processUseCase(op *openapi3.Operation, u usecase.Interactor) {
+// positionalSchema describes params passed by position as an array.
+//
+// OpenAPI 3.0 can not define schema per array item, so items can be any of param schemas
+// and order is described in text (and in x-params-by-position extension).
+func (c *OpenAPI) positionalSchema(named openapi3.SchemaOrRef, names []string) openapi3.SchemaOrRef {
+	if named.SchemaReference != nil {
+		ref := strings.TrimPrefix(named.SchemaReference.Ref, componentsSchemas)
+		named = c.Reflector().SpecEns().ComponentsEns().SchemasEns().MapOfSchemaOrRefValues[ref]
+	}
+
+	var items []openapi3.SchemaOrRef
+
+	if named.Schema != nil {
+		for _, n := range names {
+			if ps, ok := named.Schema.Properties[n]; ok {
+				items = append(items, ps)
+			}
+		}
+	}
+
+	schema := (&openapi3.Schema{}).
+		WithType(openapi3.SchemaTypeArray).
+		WithMaxItems(int64(len(names))).
+		WithDescription("Params by position: " + strings.Join(names, ", ") + ".")
+
+	if len(items) > 0 {
+		schema.WithItems(openapi3.SchemaOrRef{Schema: (&openapi3.Schema{}).WithAnyOf(items...)})
+	}
+
+	return openapi3.SchemaOrRef{Schema: schema}
+}
+
+// remove removes operation of method from OpenAPI document.
+func (c *OpenAPI) remove(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.Reflector().SpecEns().Paths.MapOfPathItemValues, name)
+}
+
+// addSecuritySchemes adds security schemes to OpenAPI components.
+func (c *OpenAPI) addSecuritySchemes(schemes map[string]openapi3.SecurityScheme) {
+	if len(schemes) == 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ss := c.Reflector().SpecEns().ComponentsEns().SecuritySchemesEns()
+
+	for name, scheme := range schemes {
+		scheme := scheme
+		ss.WithMapOfSecuritySchemeOrRefValuesItem(name, openapi3.SecuritySchemeOrRef{SecurityScheme: &scheme})
+	}
+}
+
+func (c *OpenAPI) processUseCase(op *openapi3.Operation, name string, u usecase.Interactor) {
 	var (
-		hasName        usecase.HasName
 		hasTitle       usecase.HasTitle
 		hasDescription usecase.HasDescription
 		hasTags        usecase.HasTags
 		hasDeprecated  usecase.HasIsDeprecated
 	)
 
-	if usecase.As(u, &hasName) {
-		op.WithID(hasName.Name())
-	}
+	op.WithID(name)
 
 	if usecase.As(u, &hasTitle) {
 		op.WithSummary(hasTitle.Title())
@@ -182,12 +292,11 @@ func (c *OpenAPI) processUseCase(op *openapi3.Operation, u usecase.Interactor) {
 }
 
 func (c *OpenAPI) ServeHTTP(rw http.ResponseWriter, _ *http.Request) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	document, err := json.MarshalIndent(c.Reflector().Spec, "", " ")
+	document, err := c.document()
 	if err != nil {
 		http.Error(rw, err.Error(), http.StatusInternalServerError)
+
+		return
 	}
 
 	rw.Header().Set("Content-Type", "application/json; charset=utf8")
@@ -198,6 +307,14 @@ func (c *OpenAPI) ServeHTTP(rw http.ResponseWriter, _ *http.Request) {
 	}
 }
 
+// document returns JSON encoded OpenAPI document.
+func (c *OpenAPI) document() ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return json.MarshalIndent(c.Reflector().Spec, "", " ")
+}
+
 // ProvideRequestJSONSchemas provides JSON Schemas for request structure.
 func (c *OpenAPI) provideRequestJSONSchema(
 	method string,
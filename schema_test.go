@@ -54,7 +54,17 @@ func TestOpenAPI_Collect(t *testing.T) {
 			"operationId":"nameLength",
 			"requestBody":{
 			  "content":{
-				"application/json":{"schema":{"$ref":"#/components/schemas/JsonrpcTestInp"}}
+				"application/json":{
+				  "schema":{
+					"oneOf":[
+					  {"$ref":"#/components/schemas/JsonrpcTestInp"},
+					  {
+						"maxItems":1,"type":"array","items":{"anyOf":[{"type":"string"}]},
+						"description":"Params by position: name."
+					  }
+					]
+				  }
+				}
 			  }
 			},
 			"responses":{
@@ -64,7 +74,23 @@ func TestOpenAPI_Collect(t *testing.T) {
 				  "application/json":{"schema":{"$ref":"#/components/schemas/JsonrpcTestOut"}}
 				}
 			  }
-			}
+			},
+			"x-params-by-position":["name"],
+			"x-jsonrpc-errors":[
+			  {
+				"code":-32602,"message":"invalid parameters",
+				"dataSchema":{
+				  "properties":{
+					"context":{
+					  "additionalProperties":{"items":{"type":"string"},"type":"array"},
+					  "type":["object","null"]
+					},
+					"error":{"type":"string"}
+				  },
+				  "type":"object"
+				}
+			  }
+			]
 		  }
 		}
 	  },
@@ -0,0 +1,162 @@
+package jsonrpc
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// CodeShuttingDown is an error code of call that was received during Handler.Shutdown.
+const CodeShuttingDown = ErrorCode(-32050)
+
+var errShuttingDown = errors.New("server is shutting down")
+
+// lifecycle tracks in-flight calls and persistent connections for graceful shutdown.
+type lifecycle struct {
+	mu       sync.Mutex
+	shutting bool
+	seq      uint64
+	calls    map[uint64]context.CancelFunc
+	conns    map[*Peer]struct{}
+
+	// idle is closed when there are no in-flight calls after shutdown started.
+	idle chan struct{}
+}
+
+// begin registers in-flight call, it returns false if shutdown has started.
+//
+// Returned end function must be called when call is done.
+func (l *lifecycle) begin(ctx context.Context) (context.Context, func(), bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.shutting {
+		return ctx, nil, false
+	}
+
+	if l.calls == nil {
+		l.calls = make(map[uint64]context.CancelFunc)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	l.seq++
+	id := l.seq
+	l.calls[id] = cancel
+
+	return ctx, func() {
+		cancel()
+
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		delete(l.calls, id)
+
+		if l.shutting && len(l.calls) == 0 {
+			l.closeIdle()
+		}
+	}, true
+}
+
+func (l *lifecycle) closeIdle() {
+	select {
+	case <-l.idle:
+	default:
+		close(l.idle)
+	}
+}
+
+// shutdown stops accepting calls and returns a channel that is closed when in-flight calls are done.
+func (l *lifecycle) shutdown() <-chan struct{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.shutting {
+		l.shutting = true
+		l.idle = make(chan struct{})
+
+		if len(l.calls) == 0 {
+			l.closeIdle()
+		}
+	}
+
+	return l.idle
+}
+
+// cancel cancels contexts of in-flight calls.
+func (l *lifecycle) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, cancel := range l.calls {
+		cancel()
+	}
+}
+
+// addConn registers persistent connection, it returns false if shutdown has started.
+func (l *lifecycle) addConn(p *Peer) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.shutting {
+		return false
+	}
+
+	if l.conns == nil {
+		l.conns = make(map[*Peer]struct{})
+	}
+
+	l.conns[p] = struct{}{}
+
+	return true
+}
+
+func (l *lifecycle) removeConn(p *Peer) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.conns, p)
+}
+
+// closeConns closes registered persistent connections.
+func (l *lifecycle) closeConns() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for p := range l.conns {
+		_ = p.mc.Close() //nolint:errcheck // Connection is being abandoned.
+	}
+}
+
+// isShutting checks if shutdown has started.
+func (l *lifecycle) isShutting() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.shutting
+}
+
+// Shutdown gracefully stops Handler.
+//
+// New calls are rejected with CodeShuttingDown, in-flight calls (including queued notifications)
+// are awaited. If context is done before calls are finished, their contexts are cancelled and context
+// error is returned. Persistent connections are closed after in-flight calls.
+func (h *Handler) Shutdown(ctx context.Context) error {
+	var err error
+
+	select {
+	case <-h.lifecycle.shutdown():
+	case <-ctx.Done():
+		h.lifecycle.cancel()
+
+		err = ctx.Err()
+	}
+
+	h.lifecycle.closeConns()
+
+	if dErr := h.DrainNotifications(ctx); dErr != nil && err == nil {
+		err = dErr
+	}
+
+	return err
+}
@@ -0,0 +1,96 @@
+package jsonrpc_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/jsonrpc"
+	"github.com/swaggest/usecase"
+)
+
+func TestHandler_Shutdown(t *testing.T) {
+	var started, release chan struct{}
+
+	newHandler := func() *jsonrpc.Handler {
+		started = make(chan struct{}, 1)
+		release = make(chan struct{})
+
+		u := usecase.NewIOI(nil, nil, func(ctx context.Context, input, output interface{}) error {
+			started <- struct{}{}
+
+			select {
+			case <-release:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		u.SetName("work")
+
+		h := &jsonrpc.Handler{}
+		h.Add(u)
+
+		return h
+	}
+
+	call := func(h *jsonrpc.Handler) string {
+		req, err := http.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(`{"jsonrpc":"2.0","method":"work","id":1}`)))
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		return w.Body.String()
+	}
+
+	t.Run("graceful", func(t *testing.T) {
+		h := newHandler()
+
+		resp := make(chan string)
+
+		go func() {
+			resp <- call(h)
+		}()
+
+		<-started
+
+		shutdown := make(chan error)
+
+		go func() {
+			shutdown <- h.Shutdown(context.Background())
+		}()
+
+		assert.Eventually(t, func() bool {
+			return call(h) == `{"jsonrpc":"2.0","error":{"code":-32050,"message":"server is shutting down"},"id":1}`
+		}, time.Second, time.Millisecond)
+
+		close(release)
+
+		assert.Equal(t, `{"jsonrpc":"2.0","result":null,"id":1}`, <-resp)
+		assert.NoError(t, <-shutdown)
+	})
+
+	t.Run("forced", func(t *testing.T) {
+		h := newHandler()
+
+		resp := make(chan string)
+
+		go func() {
+			resp <- call(h)
+		}()
+
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		assert.ErrorIs(t, h.Shutdown(ctx), context.DeadlineExceeded)
+		assert.Equal(t, `{"jsonrpc":"2.0","error":{"code":-32001,"message":"cancelled","data":"context canceled"},"id":1}`, <-resp)
+	})
+}
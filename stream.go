@@ -0,0 +1,164 @@
+package jsonrpc
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"strings"
+)
+
+// Framing defines how messages are delimited in a stream connection.
+type Framing int
+
+// Stream framings.
+const (
+	// FramingNewline delimits messages with new line character.
+	FramingNewline = Framing(iota)
+
+	// FramingContentLength prefixes every message with Content-Length header, as in Language Server Protocol.
+	FramingContentLength
+)
+
+// ServeConn serves JSON-RPC calls of a stream connection (e.g. stdio, TCP or Unix socket).
+//
+// It blocks until connection is closed or context is done, connection is closed on return.
+// Calls are executed concurrently and responses are sent as soon as they are ready.
+func (h *Handler) ServeConn(ctx context.Context, rwc io.ReadWriteCloser, framing Framing) error {
+	mc := &streamConn{
+		rwc:     rwc,
+		r:       bufio.NewReader(rwc),
+		framing: framing,
+		limit:   h.MaxRequestSize,
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = rwc.Close() //nolint:errcheck // Unblocking reader.
+		case <-stop:
+		}
+	}()
+
+	err := h.serveConn(ctx, mc)
+
+	if clErr := rwc.Close(); clErr != nil && err == nil && ctx.Err() == nil {
+		err = clErr
+	}
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	return err
+}
+
+type streamConn struct {
+	rwc     io.ReadWriteCloser
+	r       *bufio.Reader
+	framing Framing
+	limit   int64
+}
+
+var errMissingContentLength = errors.New("missing Content-Length header")
+
+func (s *streamConn) ReadMessage() ([]byte, error) {
+	if s.framing == FramingContentLength {
+		return s.readContentLength()
+	}
+
+	for {
+		line, err := s.readLine()
+
+		line = bytes.TrimSpace(line)
+		if len(line) > 0 {
+			return line, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (s *streamConn) readLine() ([]byte, error) {
+	var line []byte
+
+	for {
+		chunk, err := s.r.ReadSlice('\n')
+		line = append(line, chunk...)
+
+		if s.limit > 0 && int64(len(line)) > s.limit+1 {
+			return nil, errRequestTooLarge
+		}
+
+		if !errors.Is(err, bufio.ErrBufferFull) {
+			return line, err
+		}
+	}
+}
+
+func (s *streamConn) readContentLength() ([]byte, error) {
+	headers, err := textproto.NewReader(s.r).ReadMIMEHeader()
+	if err != nil {
+		// Clean EOF between messages.
+		if errors.Is(err, io.EOF) && len(headers) == 0 {
+			return nil, io.EOF
+		}
+
+		return nil, fmt.Errorf("failed to read headers: %w", err)
+	}
+
+	cl := strings.TrimSpace(headers.Get("Content-Length"))
+	if cl == "" {
+		return nil, errMissingContentLength
+	}
+
+	n, err := strconv.Atoi(cl)
+	if err != nil || n < 0 {
+		return nil, fmt.Errorf("invalid Content-Length: %q", cl)
+	}
+
+	if s.limit > 0 && int64(n) > s.limit {
+		return nil, errRequestTooLarge
+	}
+
+	data := make([]byte, n)
+
+	if _, err := io.ReadFull(s.r, data); err != nil {
+		return nil, fmt.Errorf("failed to read message: %w", err)
+	}
+
+	return data, nil
+}
+
+func (s *streamConn) WriteMessage(data []byte) error {
+	var buf []byte
+
+	if s.framing == FramingContentLength {
+		buf = make([]byte, 0, len(data)+32)
+		buf = append(buf, "Content-Length: "...)
+		buf = strconv.AppendInt(buf, int64(len(data)), 10)
+		buf = append(buf, "\r\n\r\n"...)
+		buf = append(buf, data...)
+	} else {
+		buf = make([]byte, 0, len(data)+1)
+		buf = append(buf, data...)
+		buf = append(buf, '\n')
+	}
+
+	_, err := s.rwc.Write(buf)
+
+	return err
+}
+
+func (s *streamConn) Close() error {
+	return s.rwc.Close()
+}
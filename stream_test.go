@@ -0,0 +1,227 @@
+package jsonrpc_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/jsonrpc"
+	"github.com/swaggest/usecase"
+)
+
+func TestHandler_ServeConn(t *testing.T) {
+	h := &jsonrpc.Handler{}
+	h.Add(echoUseCase())
+
+	withLength := func(msg string) string {
+		return "Content-Length: " + strconv.Itoa(len(msg)) + "\r\n\r\n" + msg
+	}
+
+	for _, tc := range []struct {
+		framing jsonrpc.Framing
+		calls   [][2]string
+	}{
+		{
+			framing: jsonrpc.FramingNewline,
+			calls: [][2]string{
+				{
+					`{"jsonrpc":"2.0","method":"echo","params":{"a":"abc","b":1},"id":1}` + "\n\n",
+					`{"jsonrpc":"2.0","result":{"b":1,"a":"abc"},"id":1}` + "\n",
+				},
+				{
+					`[{"jsonrpc":"2.0","method":"echo","params":["def",2],"id":2}]` + "\n",
+					`[{"jsonrpc":"2.0","result":{"b":2,"a":"def"},"id":2}]` + "\n",
+				},
+			},
+		},
+		{
+			framing: jsonrpc.FramingContentLength,
+			calls: [][2]string{
+				{
+					withLength(`{"jsonrpc":"2.0","method":"echo","params":{"a":"abc","b":1},"id":1}`),
+					withLength(`{"jsonrpc":"2.0","result":{"b":1,"a":"abc"},"id":1}`),
+				},
+				{
+					"content-length: 61\r\nContent-Type: application/vscode-jsonrpc; charset=utf-8\r\n\r\n" +
+						`[{"jsonrpc":"2.0","method":"echo","params":["def",2],"id":2}]`,
+					withLength(`[{"jsonrpc":"2.0","result":{"b":2,"a":"def"},"id":2}]`),
+				},
+			},
+		},
+	} {
+		srvConn, cliConn := net.Pipe()
+		done := make(chan error)
+
+		go func() {
+			done <- h.ServeConn(context.Background(), srvConn, tc.framing)
+		}()
+
+		for _, call := range tc.calls {
+			_, err := cliConn.Write([]byte(call[0]))
+			require.NoError(t, err)
+
+			resp := make([]byte, len(call[1]))
+
+			_, err = io.ReadFull(cliConn, resp)
+			require.NoError(t, err)
+			assert.Equal(t, call[1], string(resp))
+		}
+
+		require.NoError(t, cliConn.Close())
+		require.NoError(t, <-done)
+	}
+}
+
+func TestHandler_ServeConn_cancelRequest(t *testing.T) {
+	started := make(chan struct{})
+
+	u := usecase.NewIOI(nil, nil, func(ctx context.Context, input, output interface{}) error {
+		close(started)
+		<-ctx.Done()
+
+		return ctx.Err()
+	})
+	u.SetName("wait")
+
+	h := &jsonrpc.Handler{}
+	h.Add(u)
+
+	srvConn, cliConn := net.Pipe()
+	done := make(chan error)
+
+	go func() {
+		done <- h.ServeConn(context.Background(), srvConn, jsonrpc.FramingNewline)
+	}()
+
+	r := bufio.NewReader(cliConn)
+
+	_, err := cliConn.Write([]byte(`{"jsonrpc":"2.0","method":"wait","id":"abc"}` + "\n"))
+	require.NoError(t, err)
+
+	<-started
+
+	_, err = cliConn.Write([]byte(`{"jsonrpc":"2.0","method":"$/cancelRequest","params":{"id":"abc"}}` + "\n"))
+	require.NoError(t, err)
+
+	resp, err := r.ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, `{"jsonrpc":"2.0","error":{"code":-32800,"message":"request cancelled"},"id":"abc"}`+"\n", resp)
+
+	// Cancellation request with id has a response.
+	_, err = cliConn.Write([]byte(`{"jsonrpc":"2.0","method":"$/cancelRequest","params":{"id":"abc"},"id":1}` + "\n"))
+	require.NoError(t, err)
+
+	resp, err = r.ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, `{"jsonrpc":"2.0","result":null,"id":1}`+"\n", resp)
+
+	require.NoError(t, cliConn.Close())
+	require.NoError(t, <-done)
+}
+
+func TestHandler_ServeConn_maxConnInFlight(t *testing.T) {
+	started := make(chan int, 2)
+	release := make(chan struct{})
+
+	u := usecase.NewIOI(new(int), new(int), func(ctx context.Context, input, output interface{}) error {
+		started <- *input.(*int)
+		<-release
+
+		*output.(*int) = *input.(*int)
+
+		return nil
+	})
+	u.SetName("wait")
+
+	h := &jsonrpc.Handler{MaxConnInFlight: 1}
+	h.Add(u)
+
+	srvConn, cliConn := net.Pipe()
+	done := make(chan error)
+
+	go func() {
+		done <- h.ServeConn(context.Background(), srvConn, jsonrpc.FramingNewline)
+	}()
+
+	go func() {
+		_, _ = cliConn.Write([]byte(`{"jsonrpc":"2.0","method":"wait","params":1,"id":1}` + "\n" +
+			`{"jsonrpc":"2.0","method":"wait","params":2,"id":2}` + "\n"))
+	}()
+
+	assert.Equal(t, 1, <-started)
+
+	select {
+	case <-started:
+		t.Fatal("second message should not be processed while limit is reached")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+
+	r := bufio.NewReader(cliConn)
+
+	resp, err := r.ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, `{"jsonrpc":"2.0","result":1,"id":1}`+"\n", resp)
+
+	assert.Equal(t, 2, <-started)
+
+	resp, err = r.ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, `{"jsonrpc":"2.0","result":2,"id":2}`+"\n", resp)
+
+	require.NoError(t, cliConn.Close())
+	require.NoError(t, <-done)
+}
+
+// halfClosedConn has complete input and collects output.
+type halfClosedConn struct {
+	io.Reader
+
+	mu  sync.Mutex
+	out bytes.Buffer
+}
+
+func (c *halfClosedConn) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.out.Write(p)
+}
+
+func (c *halfClosedConn) Close() error {
+	return nil
+}
+
+func TestHandler_ServeConn_eof(t *testing.T) {
+	u := usecase.NewIOI(nil, new(string), func(ctx context.Context, input, output interface{}) error {
+		select {
+		case <-time.After(50 * time.Millisecond):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		*output.(*string) = "done"
+
+		return nil
+	})
+	u.SetName("slow")
+
+	h := &jsonrpc.Handler{}
+	h.Add(u)
+
+	// In-flight calls are finished after end of input.
+	c := &halfClosedConn{Reader: strings.NewReader(`{"jsonrpc":"2.0","method":"slow","id":1}` + "\n")}
+
+	require.NoError(t, h.ServeConn(context.Background(), c, jsonrpc.FramingNewline))
+	assert.Equal(t, `{"jsonrpc":"2.0","result":"done","id":1}`+"\n", c.out.String())
+}
@@ -0,0 +1,80 @@
+package jsonrpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/swaggest/usecase"
+)
+
+// TimeoutHeader is an HTTP header with client timeout of request as Go duration, for example "1.5s".
+//
+// Client timeout is respected by Handler if it is shorter than method timeout, Client sets the header
+// from context deadline. The header is ignored for calls of persistent connections.
+const TimeoutHeader = "X-Request-Timeout"
+
+// HasTimeout declares timeout of method, it overrides Handler.Timeout.
+type HasTimeout interface {
+	Timeout() time.Duration
+}
+
+// WithTimeout is a method middleware that sets timeout of method.
+func WithTimeout(timeout time.Duration) usecase.Middleware {
+	return usecase.MiddlewareFunc(func(next usecase.Interactor) usecase.Interactor {
+		return &timeoutInteractor{Interactor: next, timeout: timeout}
+	})
+}
+
+type timeoutInteractor struct {
+	usecase.Interactor
+	timeout time.Duration
+}
+
+func (t *timeoutInteractor) Timeout() time.Duration {
+	return t.timeout
+}
+
+// withTimeout applies method timeout and client timeout to context.
+func (h *Handler) withTimeout(ctx context.Context, m method) (context.Context, context.CancelFunc) {
+	timeout := m.timeout
+	if timeout == 0 {
+		timeout = h.Timeout
+	}
+
+	if ct := clientTimeout(ctx); ct > 0 && (timeout <= 0 || ct < timeout) {
+		timeout = ct
+	}
+
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
+
+// clientTimeout returns timeout from HTTP request header, invalid values are ignored.
+//
+// Only one-shot HTTP requests have client timeout, HTTP request of WebSocket connection is an upgrade request
+// that applies to the whole connection.
+func clientTimeout(ctx context.Context) time.Duration {
+	if _, ok := PeerFromContext(ctx); ok {
+		return 0
+	}
+
+	m, ok := MetaFromContext(ctx)
+	if !ok || m.HTTPRequest == nil {
+		return 0
+	}
+
+	v := m.HTTPRequest.Header.Get(TimeoutHeader)
+	if v == "" {
+		return 0
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0
+	}
+
+	return d
+}
@@ -0,0 +1,150 @@
+package jsonrpc_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/jsonrpc"
+	"github.com/swaggest/usecase"
+)
+
+func TestHandler_Timeout(t *testing.T) {
+	var deadline time.Duration
+
+	u := usecase.NewIOI(nil, nil, func(ctx context.Context, input, output interface{}) error {
+		deadline = 0
+
+		if d, ok := ctx.Deadline(); ok {
+			deadline = time.Until(d)
+		}
+
+		<-ctx.Done()
+
+		return ctx.Err()
+	})
+	u.SetName("wait")
+
+	h := jsonrpc.Handler{}
+	h.Timeout = time.Second
+	h.Add(u, jsonrpc.WithTimeout(50*time.Millisecond))
+
+	call := func(header http.Header) string {
+		req, err := http.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(`{"jsonrpc":"2.0","method":"wait","id":1}`)))
+		require.NoError(t, err)
+
+		for k, v := range header {
+			req.Header[k] = v
+		}
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		return w.Body.String()
+	}
+
+	assert.Equal(t, `{"jsonrpc":"2.0","error":{"code":-32004,"message":"deadline exceeded","data":"context deadline exceeded"},"id":1}`,
+		call(nil))
+	assert.InDelta(t, 50*time.Millisecond, deadline, float64(10*time.Millisecond))
+
+	// Shorter client timeout is respected.
+	call(http.Header{jsonrpc.TimeoutHeader: {"20ms"}})
+	assert.InDelta(t, 20*time.Millisecond, deadline, float64(10*time.Millisecond))
+
+	// Longer client timeout is ignored.
+	call(http.Header{jsonrpc.TimeoutHeader: {"10s"}})
+	assert.InDelta(t, 50*time.Millisecond, deadline, float64(10*time.Millisecond))
+}
+
+func TestHandler_Timeout_unwrapped(t *testing.T) {
+	u := usecase.NewIOI(nil, nil, func(ctx context.Context, input, output interface{}) error {
+		<-ctx.Done()
+
+		return errors.New("db query failed")
+	})
+	u.SetName("query")
+
+	h := jsonrpc.Handler{}
+	h.Timeout = 10 * time.Millisecond
+	h.Add(u)
+
+	req, err := http.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(`{"jsonrpc":"2.0","method":"query","id":1}`)))
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	// Failure after deadline is a timeout even if error does not wrap context error.
+	assert.Equal(t, `{"jsonrpc":"2.0","error":{"code":-32004,"message":"deadline exceeded","data":"db query failed"},"id":1}`,
+		w.Body.String())
+}
+
+func TestHandler_Timeout_webSocket(t *testing.T) {
+	deadlines := make(chan time.Duration, 1)
+
+	u := usecase.NewIOI(nil, nil, func(ctx context.Context, input, output interface{}) error {
+		d, _ := ctx.Deadline()
+		deadlines <- time.Until(d)
+
+		<-ctx.Done()
+
+		return ctx.Err()
+	})
+	u.SetName("wait")
+
+	h := &jsonrpc.Handler{}
+	h.Add(u, jsonrpc.WithTimeout(50*time.Millisecond))
+
+	srv := httptest.NewServer(&jsonrpc.WebSocket{Handler: h})
+	defer srv.Close()
+
+	// Timeout header of upgrade request does not apply to calls of connection.
+	c, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"),
+		http.Header{jsonrpc.TimeoutHeader: {"1ms"}})
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, c.Close())
+	}()
+
+	require.NoError(t, c.WriteMessage(websocket.TextMessage, []byte(`{"jsonrpc":"2.0","method":"wait","id":1}`)))
+	assert.InDelta(t, 50*time.Millisecond, <-deadlines, float64(10*time.Millisecond))
+}
+
+func TestClient_timeout(t *testing.T) {
+	var header string
+
+	u := usecase.NewIOI(nil, nil, func(ctx context.Context, input, output interface{}) error {
+		m, _ := jsonrpc.MetaFromContext(ctx)
+		header = m.HTTPRequest.Header.Get(jsonrpc.TimeoutHeader)
+
+		return nil
+	})
+	u.SetName("ping")
+
+	h := &jsonrpc.Handler{}
+	h.Add(u)
+
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	c := jsonrpc.Client{URL: srv.URL}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	_, err := jsonrpc.Call[struct{}, *struct{}](ctx, &c, "ping", struct{}{})
+	require.NoError(t, err)
+
+	d, err := time.ParseDuration(header)
+	require.NoError(t, err)
+	assert.InDelta(t, time.Minute, d, float64(time.Second))
+}
@@ -2,6 +2,7 @@ package jsonrpc
 
 import (
 	"bytes"
+	"sync"
 
 	"github.com/santhosh-tekuri/jsonschema/v2"
 )
@@ -15,25 +16,102 @@ type Validator interface {
 	AddResultSchema(method string, jsonSchema []byte) error
 }
 
+// SchemaRemover is implemented by Validator that can unregister schemas of removed method.
+type SchemaRemover interface {
+	RemoveSchemas(method string)
+}
+
+// SchemaReplacer is implemented by Validator that can replace schemas of method in one step.
+//
+// Nil schema removes previous schema of method.
+type SchemaReplacer interface {
+	ReplaceSchemas(method string, paramsSchema, resultSchema []byte) error
+}
+
+// pendingSchemas collects schemas of method that are applied to Validator later.
+type pendingSchemas struct {
+	Validator
+	params []byte
+	result []byte
+}
+
+func (p *pendingSchemas) AddParamsSchema(_ string, jsonSchema []byte) error {
+	p.params = jsonSchema
+
+	return nil
+}
+
+func (p *pendingSchemas) AddResultSchema(_ string, jsonSchema []byte) error {
+	p.result = jsonSchema
+
+	return nil
+}
+
+// apply replaces schemas of method in Validator.
+func (p *pendingSchemas) apply(method string) error {
+	if sr, ok := p.Validator.(SchemaReplacer); ok {
+		return sr.ReplaceSchemas(method, p.params, p.result)
+	}
+
+	if sr, ok := p.Validator.(SchemaRemover); ok {
+		sr.RemoveSchemas(method)
+	}
+
+	if p.params != nil {
+		if err := p.Validator.AddParamsSchema(method, p.params); err != nil {
+			return err
+		}
+	}
+
+	if p.result != nil {
+		return p.Validator.AddResultSchema(method, p.result)
+	}
+
+	return nil
+}
+
+// skipSchemas is a Validator that ignores registration of schemas that are provided elsewhere.
+type skipSchemas struct {
+	Validator
+}
+
+func (skipSchemas) AddParamsSchema(_ string, _ []byte) error {
+	return nil
+}
+
+func (skipSchemas) AddResultSchema(_ string, _ []byte) error {
+	return nil
+}
+
+func (skipSchemas) RemoveSchemas(_ string) {}
+
 // JSONSchemaValidator implements Validator with JSON Schema.
 type JSONSchemaValidator struct {
+	mu           sync.RWMutex
 	paramsSchema map[string]*jsonschema.Schema
 	resultSchema map[string]*jsonschema.Schema
 }
 
-func (jv *JSONSchemaValidator) addSchema(method string, isParams bool, jsonSchema []byte) error {
+func compileSchema(jsonSchema []byte) (*jsonschema.Schema, error) {
 	compiler := jsonschema.NewCompiler()
 
 	err := compiler.AddResource("schema.json", bytes.NewBuffer(jsonSchema))
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	schema, err := compiler.Compile("schema.json")
+	return compiler.Compile("schema.json")
+}
+
+func (jv *JSONSchemaValidator) addSchema(method string, isParams bool, jsonSchema []byte) error {
+	schema, err := compileSchema(jsonSchema)
 	if err != nil {
 		return err
 	}
 
+	jv.mu.Lock()
+	defer jv.mu.Unlock()
+
 	if isParams {
 		if jv.paramsSchema == nil {
 			jv.paramsSchema = make(map[string]*jsonschema.Schema)
@@ -61,6 +139,59 @@ func (jv *JSONSchemaValidator) AddResultSchema(method string, jsonSchema []byte)
 	return jv.addSchema(method, false, jsonSchema)
 }
 
+// ReplaceSchemas replaces params and result schemas of method, nil schema removes previous one.
+func (jv *JSONSchemaValidator) ReplaceSchemas(method string, paramsSchema, resultSchema []byte) error {
+	var (
+		params, result *jsonschema.Schema
+		err            error
+	)
+
+	if paramsSchema != nil {
+		if params, err = compileSchema(paramsSchema); err != nil {
+			return err
+		}
+	}
+
+	if resultSchema != nil {
+		if result, err = compileSchema(resultSchema); err != nil {
+			return err
+		}
+	}
+
+	jv.mu.Lock()
+	defer jv.mu.Unlock()
+
+	if jv.paramsSchema == nil {
+		jv.paramsSchema = make(map[string]*jsonschema.Schema)
+	}
+
+	if jv.resultSchema == nil {
+		jv.resultSchema = make(map[string]*jsonschema.Schema)
+	}
+
+	delete(jv.paramsSchema, method)
+	delete(jv.resultSchema, method)
+
+	if params != nil {
+		jv.paramsSchema[method] = params
+	}
+
+	if result != nil {
+		jv.resultSchema[method] = result
+	}
+
+	return nil
+}
+
+// RemoveSchemas unregisters schemas of method.
+func (jv *JSONSchemaValidator) RemoveSchemas(method string) {
+	jv.mu.Lock()
+	defer jv.mu.Unlock()
+
+	delete(jv.paramsSchema, method)
+	delete(jv.resultSchema, method)
+}
+
 // ValidateParams validates parameters value with JSON schema.
 func (jv *JSONSchemaValidator) ValidateParams(method string, jsonBody []byte) error {
 	return jv.validate(method, true, jsonBody)
@@ -73,15 +204,19 @@ func (jv *JSONSchemaValidator) ValidateResult(method string, jsonBody []byte) er
 
 // ValidateJSONBody performs validation of JSON body.
 func (jv *JSONSchemaValidator) validate(method string, isParams bool, jsonBody []byte) error {
-	store := jv.paramsSchema
 	name := "params"
 
+	jv.mu.RLock()
+	store := jv.paramsSchema
+
 	if !isParams {
 		store = jv.resultSchema
 		name = "result"
 	}
 
 	schema, found := store[method]
+	jv.mu.RUnlock()
+
 	if !found {
 		return nil
 	}
@@ -0,0 +1,56 @@
+package jsonrpc
+
+import (
+	"net/http"
+
+	"github.com/gorilla/websocket"
+)
+
+// WebSocket serves JSON-RPC methods of Handler over WebSocket connections.
+//
+// Every text or binary frame is a single request or a batch, calls of a connection are
+// executed concurrently and responses are sent as soon as they are ready.
+type WebSocket struct {
+	Handler  *Handler
+	Upgrader websocket.Upgrader
+}
+
+func (ws *WebSocket) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c, err := ws.Upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrader has already replied with HTTP error.
+		return
+	}
+
+	if ws.Handler.MaxRequestSize > 0 {
+		c.SetReadLimit(ws.Handler.MaxRequestSize)
+	}
+
+	mc := wsConn{c: c}
+
+	defer func() {
+		_ = mc.Close() //nolint:errcheck // Connection is done.
+	}()
+
+	ctx := ws.Handler.withHTTPRequest(r.Context(), r)
+
+	_ = ws.Handler.serveConn(ctx, mc) //nolint:errcheck // Read error means connection is closed.
+}
+
+type wsConn struct {
+	c *websocket.Conn
+}
+
+func (w wsConn) ReadMessage() ([]byte, error) {
+	_, data, err := w.c.ReadMessage()
+
+	return data, err
+}
+
+func (w wsConn) WriteMessage(data []byte) error {
+	return w.c.WriteMessage(websocket.TextMessage, data)
+}
+
+func (w wsConn) Close() error {
+	return w.c.Close()
+}
@@ -0,0 +1,150 @@
+package jsonrpc_test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/jsonrpc"
+	"github.com/swaggest/usecase"
+)
+
+func TestWebSocket_ServeHTTP(t *testing.T) {
+	h := &jsonrpc.Handler{}
+	h.Validator = &jsonrpc.JSONSchemaValidator{}
+	h.OpenAPI = &jsonrpc.OpenAPI{}
+
+	release := make(chan struct{})
+
+	u := usecase.NewInteractor(func(ctx context.Context, input struct {
+		Wait bool `json:"wait"`
+	}, output *string,
+	) error {
+		if input.Wait {
+			select {
+			case <-release:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+
+			*output = "waited"
+		} else {
+			*output = "done"
+		}
+
+		return nil
+	})
+	u.SetName("work")
+
+	h.Add(u)
+
+	srv := httptest.NewServer(&jsonrpc.WebSocket{Handler: h})
+	defer srv.Close()
+
+	c, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, c.Close())
+	}()
+
+	require.NoError(t, c.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+	require.NoError(t, c.WriteMessage(websocket.TextMessage, []byte(`{"jsonrpc":"2.0","method":"work","params":{"wait":true},"id":1}`)))
+	require.NoError(t, c.WriteMessage(websocket.TextMessage, []byte(`[{"jsonrpc":"2.0","method":"work","params":{},"id":2},{"jsonrpc":"2.0","method":"unknown","id":3}]`)))
+
+	// Second call completes before the first one.
+	_, msg, err := c.ReadMessage()
+	require.NoError(t, err)
+	assert.Equal(t, `[{"jsonrpc":"2.0","result":"done","id":2},{"jsonrpc":"2.0","error":{"code":-32601,"message":"method not found: unknown"},"id":3}]`, string(msg))
+
+	close(release)
+
+	_, msg, err = c.ReadMessage()
+	require.NoError(t, err)
+	assert.Equal(t, `{"jsonrpc":"2.0","result":"waited","id":1}`, string(msg))
+
+	require.NoError(t, c.WriteMessage(websocket.TextMessage, []byte(`{"jsonrpc":`)))
+
+	_, msg, err = c.ReadMessage()
+	require.NoError(t, err)
+	assert.Equal(t, `{"jsonrpc":"2.0","error":{"code":-32700,"message":"failed to unmarshal request: unexpected end of JSON input"},"id":null}`, string(msg))
+}
+
+func TestPeer_Subscribe(t *testing.T) {
+	h := &jsonrpc.Handler{}
+
+	subscriptionDone := make(chan struct{})
+
+	u := usecase.NewInteractor(func(ctx context.Context, input struct {
+		Count int `json:"count"`
+	}, output *string,
+	) error {
+		p, ok := jsonrpc.PeerFromContext(ctx)
+		if !ok {
+			return errors.New("no peer")
+		}
+
+		s := p.Subscribe("ticks")
+		*output = s.ID
+
+		go func() {
+			defer close(subscriptionDone)
+
+			// Waiting for subscription id to be delivered before events.
+			time.Sleep(10 * time.Millisecond)
+
+			for i := 0; i < input.Count; i++ {
+				if err := s.Notify(i); err != nil {
+					return
+				}
+			}
+
+			<-s.Done()
+		}()
+
+		return nil
+	})
+	u.SetName("subscribe")
+
+	h.Add(u)
+	h.Add(jsonrpc.UnsubscribeUseCase("unsubscribe"))
+
+	srv := httptest.NewServer(&jsonrpc.WebSocket{Handler: h})
+	defer srv.Close()
+
+	c, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, c.Close())
+	}()
+
+	require.NoError(t, c.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+	require.NoError(t, c.WriteMessage(websocket.TextMessage, []byte(`{"jsonrpc":"2.0","method":"subscribe","params":{"count":2},"id":1}`)))
+
+	for _, expected := range []string{
+		`{"jsonrpc":"2.0","result":"0x1","id":1}`,
+		`{"jsonrpc":"2.0","method":"ticks","params":{"subscription":"0x1","result":0}}`,
+		`{"jsonrpc":"2.0","method":"ticks","params":{"subscription":"0x1","result":1}}`,
+	} {
+		_, msg, err := c.ReadMessage()
+		require.NoError(t, err)
+		assert.Equal(t, expected, string(msg))
+	}
+
+	require.NoError(t, c.WriteMessage(websocket.TextMessage, []byte(`{"jsonrpc":"2.0","method":"unsubscribe","params":["0x1"],"id":2}`)))
+
+	_, msg, err := c.ReadMessage()
+	require.NoError(t, err)
+	assert.Equal(t, `{"jsonrpc":"2.0","result":true,"id":2}`, string(msg))
+
+	<-subscriptionDone
+}